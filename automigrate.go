@@ -0,0 +1,127 @@
+package sqlxx
+
+import (
+	"context"
+	"fmt"
+	"github.com/fatih/structs"
+	"github.com/jmoiron/sqlx"
+	"strings"
+)
+
+// AutoMigrate creates the missing tables, columns and indexes of models.
+// It never drops or alters what already exists. A NOT NULL column added
+// to an existing table needs a default and a unique one gets a separate
+// unique index.
+func AutoMigrate(ctx context.Context, db *sqlx.DB, models ...interface{}) error {
+	stmts, err := AutoMigrateDryRun(ctx, db, models...)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AutoMigrateDryRun returns the statements AutoMigrate would run.
+func AutoMigrateDryRun(ctx context.Context, db *sqlx.DB, models ...interface{}) ([]string, error) {
+	d := DialectOf(db.DriverName())
+	var stmts []string
+	for _, model := range models {
+		s, err := migrateStatements(ctx, db, d, model)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, s...)
+	}
+	return stmts, nil
+}
+
+func migrateStatements(ctx context.Context, db *sqlx.DB, d Dialect, model interface{}) ([]string, error) {
+	s := structs.New(model)
+	table := setTableName(ctx, model, s)
+	existing, err := tableColumns(ctx, db, d, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return createTableStatements(ctx, model, d)
+	}
+	existingIndexes, err := tableIndexes(ctx, db, d, table)
+	if err != nil {
+		return nil, err
+	}
+	return alterStatements(table, columns(s), d, existing, existingIndexes)
+}
+
+// alterStatements adds to an existing table the columns and indexes of cs
+// it lacks.
+func alterStatements(table string, cs []column, d Dialect, existing, existingIndexes map[string]bool) ([]string, error) {
+	var stmts []string
+	idxs := indexes(table, cs)
+	for _, c := range cs {
+		if existing[strings.ToLower(c.name)] {
+			continue
+		}
+		// the rows already there get NULL unless there is a default
+		if _, ok := c.tag["notnull"]; ok && c.tag["default"] == "" {
+			return nil, fmt.Errorf("cannot add NOT NULL column %s to %s without a default", c.name, table)
+		}
+		def, err := columnDef(c, d, false)
+		if err != nil {
+			return nil, err
+		}
+		// sqlite cannot add a UNIQUE column and a populated table may hold
+		// duplicates, index it instead so the failure is on its own
+		if v, ok := c.tag["unique"]; ok && v == "" {
+			def = strings.Replace(def, " UNIQUE", "", 1)
+			idxs = append(idxs, index{name: "uk_" + table + "_" + c.name, unique: true, columns: []string{c.name}})
+		}
+		stmts = append(stmts, "ALTER TABLE "+d.quote(table)+" ADD COLUMN "+def)
+	}
+	for _, idx := range idxs {
+		if !existingIndexes[strings.ToLower(idx.name)] {
+			stmts = append(stmts, createIndexSQL(table, idx, d))
+		}
+	}
+	return stmts, nil
+}
+
+// tableColumns returns the lower cased column names of table, none if the
+// table does not exist.
+func tableColumns(ctx context.Context, db *sqlx.DB, d Dialect, table string) (map[string]bool, error) {
+	cs, err := loadColumns(ctx, db, d, table)
+	if err != nil {
+		return nil, err
+	}
+	cols := make(map[string]bool, len(cs))
+	for _, c := range cs {
+		cols[strings.ToLower(c.Name)] = true
+	}
+	return cols, nil
+}
+
+func tableIndexes(ctx context.Context, db *sqlx.DB, d Dialect, table string) (map[string]bool, error) {
+	var names []string
+	var err error
+	switch d {
+	case MySQL:
+		err = db.SelectContext(ctx, &names, "SELECT DISTINCT index_name FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ?", table)
+	case Postgres:
+		err = db.SelectContext(ctx, &names, "SELECT indexname FROM pg_indexes WHERE schemaname = current_schema() AND tablename = $1", table)
+	case SQLite:
+		err = db.SelectContext(ctx, &names, "SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = ?", table)
+	default:
+		return nil, fmt.Errorf("unsupport dialect %s", d)
+	}
+	if err != nil {
+		return nil, err
+	}
+	idxs := make(map[string]bool, len(names))
+	for _, n := range names {
+		idxs[strings.ToLower(n)] = true
+	}
+	return idxs, nil
+}
@@ -0,0 +1,56 @@
+package sqlxx
+
+import (
+	"github.com/fatih/structs"
+	"reflect"
+	"testing"
+)
+
+type Stock struct {
+	Id     int64  `db:"id"`
+	Code   string `db:"code" sqlxx:"size:32;unique"`
+	Qty    int    `db:"qty" sqlxx:"notnull;default:0"`
+	OnSale bool   `db:"on_sale" sqlxx:"index"`
+}
+
+func TestAlterStatements(t *testing.T) {
+	existing := map[string]bool{"id": true, "name": true, "state": true, "price": true, "memo": true, "created_at": true}
+	existingIndexes := map[string]bool{"primary": true, "idx_product_name_state": true}
+	_, err := alterStatements("product", columns(structs.New(&Product{})), MySQL, existing, existingIndexes)
+	if err == nil || err.Error() != "cannot add NOT NULL column sku to product without a default" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	existing = map[string]bool{"id": true}
+	existingIndexes = map[string]bool{"primary": true}
+	cs := columns(structs.New(&Stock{}))
+	stmts, err := alterStatements("stock", cs, MySQL, existing, existingIndexes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"ALTER TABLE `stock` ADD COLUMN `code` varchar(32)",
+		"ALTER TABLE `stock` ADD COLUMN `qty` bigint NOT NULL DEFAULT 0",
+		"ALTER TABLE `stock` ADD COLUMN `on_sale` tinyint(1)",
+		"CREATE INDEX `idx_stock_on_sale` ON `stock` (`on_sale`)",
+		"CREATE UNIQUE INDEX `uk_stock_code` ON `stock` (`code`)",
+	}
+	if !reflect.DeepEqual(stmts, want) {
+		t.Errorf("got %q, want %q", stmts, want)
+	}
+
+	stmts, err = alterStatements("stock", cs, SQLite, existing, existingIndexes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{
+		`ALTER TABLE "stock" ADD COLUMN "code" text`,
+		`ALTER TABLE "stock" ADD COLUMN "qty" bigint NOT NULL DEFAULT 0`,
+		`ALTER TABLE "stock" ADD COLUMN "on_sale" integer`,
+		`CREATE INDEX "idx_stock_on_sale" ON "stock" ("on_sale")`,
+		`CREATE UNIQUE INDEX "uk_stock_code" ON "stock" ("code")`,
+	}
+	if !reflect.DeepEqual(stmts, want) {
+		t.Errorf("got %q, want %q", stmts, want)
+	}
+}
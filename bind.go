@@ -0,0 +1,85 @@
+package sqlxx
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// namedParams returns the :name parameters of sql the way sqlx reads
+// them, "::" being an escaped colon and ":=" an assignment.
+func namedParams(sql string) []string {
+	var names []string
+	for i := 0; i < len(sql); i++ {
+		if sql[i] != ':' {
+			continue
+		}
+		if i+1 < len(sql) && (sql[i+1] == ':' || sql[i+1] == '=') {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(sql) && isBindRune(rune(sql[j])) {
+			j++
+		}
+		if j > i+1 {
+			names = append(names, sql[i+1:j])
+		}
+		i = j - 1
+	}
+	return names
+}
+
+func isBindRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
+
+// isNamed reports whether sql binds :name parameters from the single
+// struct or map of args.
+func isNamed(sql string, args []interface{}) bool {
+	return len(args) == 1 && namedArg(args[0]) && len(namedParams(sql)) > 0
+}
+
+// namedArg reports whether v binds :name parameters, that is a
+// map[string]interface{} or a struct that is not a single value such as
+// time.Time or sql.NullString.
+func namedArg(v interface{}) bool {
+	if _, ok := v.(map[string]interface{}); ok {
+		return true
+	}
+	if _, ok := v.(driver.Valuer); ok {
+		return false
+	}
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t != nil && t.Kind() == reflect.Struct && t != reflect.TypeOf(time.Time{})
+}
+
+// bindNamed binds the :name parameters of sql from arg and rebinds it
+// for the database, reporting all the parameters arg lacks at once.
+func (sqlxx *Sqlxx) bindNamed(sql string, arg interface{}) (string, []interface{}, error) {
+	var missing []string
+	names := namedParams(sql)
+	if m, ok := arg.(map[string]interface{}); ok {
+		for _, name := range names {
+			if _, ok := m[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+	} else {
+		for i, tr := range sqlxx.db.Mapper.TraversalsByName(reflect.TypeOf(arg), names) {
+			if len(tr) == 0 {
+				missing = append(missing, names[i])
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("missing named parameters %s", strings.Join(missing, ", "))
+	}
+	return sqlxx.db.BindNamed(sql, arg)
+}
@@ -0,0 +1,52 @@
+package sqlxx
+
+import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"reflect"
+	"testing"
+)
+
+func TestNamedParams(t *testing.T) {
+	got := namedParams("SELECT id::text FROM user WHERE name = :name AND age > :min_age AND a.b = :addr.city")
+	if want := []string{"name", "min_age", "addr.city"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSqlxx_BindNamed(t *testing.T) {
+	db := sqlx.NewDb(&sql.DB{}, "postgres")
+	setMapper(db)
+	sqlxx := &Sqlxx{dest: &UserInfo{}, db: db}
+	update := "UPDATE user SET name = :name, age = :age WHERE id = :id"
+
+	sqls, args, err := sqlxx.render("update", update, []interface{}{&UserInfo{Id: 2, Name: "abc", Age: 11}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "UPDATE user SET name = $1, age = $2 WHERE id = $3"; sqls != want {
+		t.Errorf("got %s, want %s", sqls, want)
+	}
+	if want := []interface{}{"abc", 11, 2}; !reflect.DeepEqual(args, want) {
+		t.Errorf("got %v, want %v", args, want)
+	}
+
+	_, args, err = sqlxx.render("update", update, []interface{}{map[string]interface{}{"name": "abc", "age": 11, "id": 2}})
+	if want := []interface{}{"abc", 11, 2}; err != nil || !reflect.DeepEqual(args, want) {
+		t.Errorf("got %v %v, want %v", args, err, want)
+	}
+
+	_, _, err = sqlxx.render("update", update, []interface{}{map[string]interface{}{"age": 11}})
+	if err == nil || err.Error() != "missing named parameters name, id" {
+		t.Errorf("got %v", err)
+	}
+	_, _, err = sqlxx.render("update", "UPDATE user SET name = :name WHERE uid = :uid", []interface{}{UserInfo{}})
+	if err == nil || err.Error() != "missing named parameters uid" {
+		t.Errorf("got %v", err)
+	}
+
+	sqls, args, _ = sqlxx.render("count", "SELECT count(*) FROM user WHERE name = ?", []interface{}{sql.NullString{String: "abc", Valid: true}})
+	if sqls != "SELECT count(*) FROM user WHERE name = ?" || len(args) != 1 {
+		t.Errorf("positional args were bound by name: %s %v", sqls, args)
+	}
+}
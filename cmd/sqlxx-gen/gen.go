@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/hoperuin/sqlxx"
+	"github.com/jmoiron/sqlx"
+	"go/format"
+	"path"
+	"strings"
+	"unicode"
+)
+
+type options struct {
+	pkg     string
+	driver  string
+	include []string
+	exclude []string
+	// mapper generates the Select, SelectOne, Save, Update, Delete and
+	// Count methods used by the Sqlxx Mapper interfaces
+	mapper bool
+}
+
+// selected reports whether table passes the include and exclude
+// patterns, both matched with path.Match.
+func (o options) selected(table string) bool {
+	match := func(patterns []string) bool {
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, table); ok {
+				return true
+			}
+		}
+		return false
+	}
+	if len(o.include) > 0 && !match(o.include) {
+		return false
+	}
+	return !match(o.exclude)
+}
+
+// generate returns the gofmt'ed source of the models of tables, in table
+// order so that regenerating an unchanged schema gives the same file.
+func generate(tables []sqlxx.TableInfo, o options) ([]byte, error) {
+	var body bytes.Buffer
+	imports := map[string]bool{}
+	for _, t := range tables {
+		if !o.selected(t.Name) {
+			continue
+		}
+		writeModel(&body, t, o, imports)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by sqlxx-gen. DO NOT EDIT.\n\npackage %s\n\n", o.pkg)
+	if len(imports) > 0 {
+		buf.WriteString("import (\n")
+		for _, imp := range []string{"database/sql", "time"} {
+			if imports[imp] {
+				fmt.Fprintf(&buf, "\t%q\n", imp)
+			}
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(body.Bytes())
+	return format.Source(buf.Bytes())
+}
+
+func writeModel(buf *bytes.Buffer, t sqlxx.TableInfo, o options, imports map[string]bool) {
+	name := goName(t.Name)
+	recv := strings.ToLower(name[:1])
+	var pk, cols, insertCols, setCols []string
+	d := sqlxx.DialectOf(o.driver)
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, c := range t.Columns {
+		typ, imp := goType(c, d)
+		if imp != "" {
+			imports[imp] = true
+		}
+		tag := fmt.Sprintf("db:%q", c.Name)
+		if c.PrimaryKey {
+			tag += ` pk:"true"`
+			pk = append(pk, c.Name)
+		} else {
+			setCols = append(setCols, c.Name)
+		}
+		if !c.AutoIncrement {
+			insertCols = append(insertCols, c.Name)
+		}
+		cols = append(cols, c.Name)
+		fmt.Fprintf(buf, "\t%s %s `%s`\n", goName(c.Name), typ, tag)
+	}
+	buf.WriteString("}\n\n")
+	fmt.Fprintf(buf, "func (%s *%s) TableName() string {\n\treturn %q\n}\n\n", recv, name, t.Name)
+	if !o.mapper {
+		return
+	}
+
+	bind := sqlx.BindType(string(d))
+	method := func(m, sql string) {
+		fmt.Fprintf(buf, "func (%s *%s) %s() string {\n\treturn %q\n}\n\n", recv, name, m, sqlx.Rebind(bind, sql))
+	}
+	where := ""
+	if len(pk) > 0 {
+		where = " WHERE " + strings.Join(pk, " = ? AND ") + " = ?"
+	}
+	selectSQL := "SELECT " + strings.Join(cols, ", ") + " FROM " + t.Name
+	method("Select", selectSQL)
+	if where != "" {
+		method("SelectOne", selectSQL+where)
+	}
+	// a table of a single auto increment column has nothing to insert
+	if len(insertCols) > 0 {
+		method("Save", "INSERT INTO "+t.Name+" ("+strings.Join(insertCols, ", ")+") VALUES (?"+strings.Repeat(", ?", len(insertCols)-1)+")")
+	}
+	if where != "" && len(setCols) > 0 {
+		method("Update", "UPDATE "+t.Name+" SET "+strings.Join(setCols, " = ?, ")+" = ?"+where)
+	}
+	if where != "" {
+		method("Delete", "DELETE FROM "+t.Name+where)
+	}
+	method("Count", "SELECT count(*) FROM "+t.Name)
+}
+
+// goName turns a table or column name into an exported identifier,
+// user_info gives UserInfo.
+func goName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(r) {
+			b.WriteByte('X')
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "X"
+	}
+	return b.String()
+}
+
+// goType maps a column to its Go type and the package the type needs,
+// nullable columns get a sql.Null type or else a pointer.
+func goType(c sqlxx.ColumnInfo, d sqlxx.Dialect) (string, string) {
+	t := c.Type
+	unsigned := strings.Contains(t, "unsigned")
+	base := t
+	if i := strings.IndexAny(base, "( "); i >= 0 {
+		base = base[:i]
+	}
+
+	typ := "string"
+	switch base {
+	case "bool", "boolean":
+		typ = "bool"
+	case "tinyint":
+		typ = "int8"
+		if strings.HasPrefix(t, "tinyint(1)") {
+			typ = "bool"
+		}
+	case "smallint", "int2", "smallserial":
+		typ = "int16"
+	case "mediumint", "int", "int4", "serial":
+		typ = "int32"
+	case "integer":
+		// sqlite integers are 64 bits
+		typ = "int32"
+		if d == sqlxx.SQLite {
+			typ = "int64"
+		}
+	case "bigint", "int8", "bigserial":
+		typ = "int64"
+	case "float", "real", "float4":
+		typ = "float32"
+	case "double", "float8":
+		typ = "float64"
+	case "decimal", "numeric":
+		// kept as string, float64 would lose precision
+		typ = "string"
+	case "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary", "bytea":
+		return "[]byte", ""
+	case "date", "datetime", "timestamp":
+		typ = "time.Time"
+	}
+	if unsigned && strings.HasPrefix(typ, "int") {
+		typ = "u" + typ
+	}
+
+	imp := ""
+	if typ == "time.Time" {
+		imp = "time"
+	}
+	if !c.Nullable {
+		return typ, imp
+	}
+	switch typ {
+	case "string":
+		return "sql.NullString", "database/sql"
+	case "bool":
+		return "sql.NullBool", "database/sql"
+	case "int16":
+		return "sql.NullInt16", "database/sql"
+	case "int32":
+		return "sql.NullInt32", "database/sql"
+	case "int64":
+		return "sql.NullInt64", "database/sql"
+	case "float64":
+		return "sql.NullFloat64", "database/sql"
+	case "time.Time":
+		return "sql.NullTime", "database/sql"
+	}
+	return "*" + typ, imp
+}
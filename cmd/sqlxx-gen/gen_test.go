@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"github.com/hoperuin/sqlxx"
+	"github.com/jmoiron/sqlx"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	db, err := sqlx.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.MustExec("CREATE TABLE user_info (id INTEGER PRIMARY KEY, name varchar(20) NOT NULL, email varchar(50), created_at datetime)")
+	db.MustExec("CREATE TABLE audit_log (id INTEGER PRIMARY KEY, body text)")
+	tables, err := sqlxx.LoadSchema(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := generate(tables, options{pkg: "model", driver: "sqlite3", exclude: []string{"audit_*"}, mapper: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "// Code generated by sqlxx-gen. DO NOT EDIT.\n\n" +
+		"package model\n\n" +
+		"import (\n\t\"database/sql\"\n)\n\n" +
+		"type UserInfo struct {\n" +
+		"\tId        int64          `db:\"id\" pk:\"true\"`\n" +
+		"\tName      string         `db:\"name\"`\n" +
+		"\tEmail     sql.NullString `db:\"email\"`\n" +
+		"\tCreatedAt sql.NullTime   `db:\"created_at\"`\n" +
+		"}\n\n" +
+		"func (u *UserInfo) TableName() string {\n\treturn \"user_info\"\n}\n\n" +
+		"func (u *UserInfo) Select() string {\n\treturn \"SELECT id, name, email, created_at FROM user_info\"\n}\n\n" +
+		"func (u *UserInfo) SelectOne() string {\n\treturn \"SELECT id, name, email, created_at FROM user_info WHERE id = ?\"\n}\n\n" +
+		"func (u *UserInfo) Save() string {\n\treturn \"INSERT INTO user_info (name, email, created_at) VALUES (?, ?, ?)\"\n}\n\n" +
+		"func (u *UserInfo) Update() string {\n\treturn \"UPDATE user_info SET name = ?, email = ?, created_at = ? WHERE id = ?\"\n}\n\n" +
+		"func (u *UserInfo) Delete() string {\n\treturn \"DELETE FROM user_info WHERE id = ?\"\n}\n\n" +
+		"func (u *UserInfo) Count() string {\n\treturn \"SELECT count(*) FROM user_info\"\n}\n"
+	if string(src) != want {
+		t.Errorf("got\n%s\nwant\n%s", src, want)
+	}
+}
+
+func TestGenerate_AutoIncrementOnly(t *testing.T) {
+	tables := []sqlxx.TableInfo{{
+		Name:    "ticket",
+		Columns: []sqlxx.ColumnInfo{{Name: "id", Type: "bigint", PrimaryKey: true, AutoIncrement: true}},
+	}}
+	src, err := generate(tables, options{pkg: "model", driver: "pgx", mapper: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(src), "Save()") {
+		t.Errorf("unexpected Save in\n%s", src)
+	}
+	if !strings.Contains(string(src), "WHERE id = $1") {
+		t.Errorf("pgx not bound as postgres in\n%s", src)
+	}
+}
+
+func TestGoType(t *testing.T) {
+	cases := []struct {
+		c    sqlxx.ColumnInfo
+		d    sqlxx.Dialect
+		want string
+	}{
+		{sqlxx.ColumnInfo{Type: "int(10) unsigned"}, sqlxx.MySQL, "uint32"},
+		{sqlxx.ColumnInfo{Type: "tinyint(1)"}, sqlxx.MySQL, "bool"},
+		{sqlxx.ColumnInfo{Type: "bigint", Nullable: true}, sqlxx.MySQL, "sql.NullInt64"},
+		{sqlxx.ColumnInfo{Type: "timestamp without time zone"}, sqlxx.Postgres, "time.Time"},
+		{sqlxx.ColumnInfo{Type: "real", Nullable: true}, sqlxx.Postgres, "*float32"},
+		{sqlxx.ColumnInfo{Type: "bytea", Nullable: true}, sqlxx.Postgres, "[]byte"},
+		{sqlxx.ColumnInfo{Type: "decimal(10,2)"}, sqlxx.MySQL, "string"},
+		{sqlxx.ColumnInfo{Type: "numeric", Nullable: true}, sqlxx.Postgres, "sql.NullString"},
+	}
+	for _, c := range cases {
+		if got, _ := goType(c.c, c.d); got != c.want {
+			t.Errorf("goType(%q) = %s, want %s", c.c.Type, got, c.want)
+		}
+	}
+}
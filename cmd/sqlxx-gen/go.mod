@@ -0,0 +1,16 @@
+module github.com/hoperuin/sqlxx/cmd/sqlxx-gen
+
+go 1.18
+
+require (
+	github.com/go-sql-driver/mysql v1.4.0
+	github.com/hoperuin/sqlxx v0.0.0-20261019054613-98cc3f5386b0
+	github.com/jmoiron/sqlx v1.2.0
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.16
+)
+
+require (
+	github.com/fatih/structs v1.1.0 // indirect
+	google.golang.org/appengine v1.5.0 // indirect
+)
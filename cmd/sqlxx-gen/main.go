@@ -0,0 +1,71 @@
+// Command sqlxx-gen generates sqlxx model structs from the tables of a
+// MySQL, Postgres or SQLite database.
+//
+//	go install github.com/hoperuin/sqlxx/cmd/sqlxx-gen@latest
+//	sqlxx-gen -driver mysql -dsn 'root:pw@tcp(localhost:3306)/test' -pkg model -out model/model.go
+package main
+
+import (
+	"context"
+	"flag"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/hoperuin/sqlxx"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	driver := flag.String("driver", "mysql", "database driver: mysql, postgres or sqlite3")
+	dsn := flag.String("dsn", "", "data source name")
+	pkg := flag.String("pkg", "model", "package name of the generated file")
+	out := flag.String("out", "", "output file, stdout if empty")
+	include := flag.String("include", "", "comma separated table patterns to generate, all if empty")
+	exclude := flag.String("exclude", "", "comma separated table patterns to skip")
+	mapper := flag.Bool("mapper", false, "generate Select, SelectOne, Save, Update, Delete and Count methods")
+	flag.Parse()
+	if *dsn == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	db, err := sqlx.Open(*driver, *dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	tables, err := sqlxx.LoadSchema(context.Background(), db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(tables, options{
+		pkg:     *pkg,
+		driver:  *driver,
+		include: patterns(*include),
+		exclude: patterns(*exclude),
+		mapper:  *mapper,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func patterns(s string) []string {
+	var ps []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
@@ -0,0 +1,230 @@
+package sqlxx
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type Cursors struct {
+	Next string
+	Prev string
+}
+
+func (q *query) After(cursor string) *query {
+	q.after, q.before = cursor, ""
+	return q
+}
+
+func (q *query) Before(cursor string) *query {
+	q.after, q.before = "", cursor
+	return q
+}
+
+// Page lists one page of rows into dest, a pointer to a slice, seeking
+// past the After/Before cursor on the Order columns. The returned cursors
+// are empty when there is no page in that direction.
+func (q *query) Page(dest interface{}) (Cursors, error) {
+	var c Cursors
+	if q.limit <= 0 {
+		return c, errors.New("page must be set limit")
+	}
+	if len(q.order) == 0 {
+		return c, errors.New("page must be set order")
+	}
+
+	limit := q.limit
+	q.limit = limit + 1
+	sql, err := q.build()
+	q.limit = limit
+	if err != nil {
+		return c, err
+	}
+	err = q.list(dest, sql, q.whereValue)
+	if err != nil {
+		return c, err
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(dest))
+	more := rv.Len() > limit
+	if more {
+		rv.Set(rv.Slice(0, limit))
+	}
+	if q.before != "" {
+		swap := reflect.Swapper(rv.Interface())
+		for i, j := 0, rv.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+	if rv.Len() == 0 {
+		return c, nil
+	}
+
+	first, err := q.cursorOf(rv.Index(0))
+	if err != nil {
+		return c, err
+	}
+	last, err := q.cursorOf(rv.Index(rv.Len() - 1))
+	if err != nil {
+		return c, err
+	}
+	if q.before != "" {
+		c.Next = last
+		if more {
+			c.Prev = first
+		}
+	} else {
+		if more {
+			c.Next = last
+		}
+		if q.after != "" {
+			c.Prev = first
+		}
+	}
+	return c, nil
+}
+
+func (q *query) cursorOf(row reflect.Value) (string, error) {
+	row = reflect.Indirect(row)
+	values := make([]interface{}, 0, len(q.order))
+	for _, o := range q.order {
+		name := o.key
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		f := q.db.Mapper.FieldByName(row, name)
+		if !f.IsValid() {
+			return "", fmt.Errorf("cursor column %s not found in result", o.key)
+		}
+		v := f.Interface()
+		if valuer, ok := v.(driver.Valuer); ok {
+			dv, err := valuer.Value()
+			if err != nil {
+				return "", err
+			}
+			v = dv
+		}
+		values = append(values, v)
+	}
+	return encodeCursor(values)
+}
+
+// keyset renders the seek predicate for the current cursor, using a row
+// value comparison when every Order column sorts the same way and the
+// dialect supports it, and the expanded OR form otherwise.
+func (q *query) keyset() (string, []interface{}, error) {
+	cursor, before := q.after, false
+	if q.before != "" {
+		cursor, before = q.before, true
+	}
+	if cursor == "" {
+		return "", nil, nil
+	}
+	values, err := decodeCursor(cursor)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(values) != len(q.order) {
+		return "", nil, errors.New("cursor does not match order columns")
+	}
+
+	for _, o := range q.order {
+		if len(o.keyArgs) > 0 {
+			return "", nil, errors.New("cursor does not support order expressions with args")
+		}
+	}
+
+	ops := make([]string, len(q.order))
+	sameOp := true
+	for i, o := range q.order {
+		desc := isDesc(o.value.(string))
+		if desc == before {
+			ops[i] = ">"
+		} else {
+			ops[i] = "<"
+		}
+		if ops[i] != ops[0] {
+			sameOp = false
+		}
+	}
+
+	var sb bytes.Buffer
+	if len(q.order) == 1 {
+		sb.WriteString(q.order[0].key + " " + ops[0] + " ?")
+		return sb.String(), values, nil
+	}
+
+	if sameOp && q.dialect().rowValues() {
+		keys := make([]string, len(q.order))
+		marks := make([]string, len(q.order))
+		for i, o := range q.order {
+			keys[i], marks[i] = o.key, "?"
+		}
+		sb.WriteString("(" + strings.Join(keys, ", ") + ") " + ops[0] + " (" + strings.Join(marks, ", ") + ")")
+		return sb.String(), values, nil
+	}
+
+	var args []interface{}
+	sb.WriteString("(")
+	for i := range q.order {
+		if i > 0 {
+			sb.WriteString(" OR ")
+		}
+		sb.WriteString("(")
+		for j := 0; j < i; j++ {
+			sb.WriteString(q.order[j].key + " = ? AND ")
+			args = append(args, values[j])
+		}
+		sb.WriteString(q.order[i].key + " " + ops[i] + " ?)")
+		args = append(args, values[i])
+	}
+	sb.WriteString(")")
+	return sb.String(), args, nil
+}
+
+func isDesc(order string) bool {
+	return strings.EqualFold(strings.TrimSpace(order), "desc")
+}
+
+func reverseOrder(order string) string {
+	if isDesc(order) {
+		return "asc"
+	}
+	return "desc"
+}
+
+func encodeCursor(values []interface{}) (string, error) {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(cursor string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	var values []interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&values); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	for i, v := range values {
+		if n, ok := v.(json.Number); ok {
+			if iv, err := n.Int64(); err == nil {
+				values[i] = iv
+			} else {
+				values[i] = n.String()
+			}
+		}
+	}
+	return values, nil
+}
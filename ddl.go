@@ -0,0 +1,248 @@
+package sqlxx
+
+import (
+	"context"
+	"fmt"
+	"github.com/fatih/structs"
+	"github.com/jmoiron/sqlx"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+type index struct {
+	name    string
+	unique  bool
+	columns []string
+}
+
+// CreateTableSQL returns the CREATE TABLE and CREATE INDEX statements of
+// model for the dialect d, column types come from the Go field types
+// unless set with the size or type tag options.
+func CreateTableSQL(model interface{}, d Dialect) (string, error) {
+	stmts, err := createTableStatements(context.Background(), model, d)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(stmts, ";\n") + ";", nil
+}
+
+func CreateTable(ctx context.Context, db *sqlx.DB, model interface{}) error {
+	stmts, err := createTableStatements(ctx, model, DialectOf(db.DriverName()))
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createTableStatements(ctx context.Context, model interface{}, d Dialect) ([]string, error) {
+	s := structs.New(model)
+	table := setTableName(ctx, model, s)
+	pk, ok := findPkColumn(s)
+	if !ok {
+		return nil, noPkError(s)
+	}
+	cs := columns(s)
+
+	defs := make([]string, 0, len(cs))
+	for _, c := range cs {
+		def, err := columnDef(c, d, c.name == pk.name)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+
+	stmts := []string{"CREATE TABLE " + d.quote(table) + " (\n  " + strings.Join(defs, ",\n  ") + "\n)"}
+	for _, idx := range indexes(table, cs) {
+		stmts = append(stmts, createIndexSQL(table, idx, d))
+	}
+	return stmts, nil
+}
+
+func createIndexSQL(table string, idx index, d Dialect) string {
+	quoted := make([]string, len(idx.columns))
+	for i, c := range idx.columns {
+		quoted[i] = d.quote(c)
+	}
+	unique := ""
+	if idx.unique {
+		unique = "UNIQUE "
+	}
+	return "CREATE " + unique + "INDEX " + d.quote(idx.name) + " ON " + d.quote(table) + " (" + strings.Join(quoted, ",") + ")"
+}
+
+// indexes collects the index and named unique tag options of cs, fields
+// sharing an index name make up one composite index.
+func indexes(table string, cs []column) []index {
+	byName := make(map[string]*index)
+	var names []string
+	add := func(name string, unique bool, col string) {
+		idx, ok := byName[name]
+		if !ok {
+			idx = &index{name: name, unique: unique}
+			byName[name] = idx
+			names = append(names, name)
+		}
+		idx.columns = append(idx.columns, col)
+	}
+	for _, c := range cs {
+		if name, ok := c.tag["index"]; ok {
+			if name == "" {
+				name = "idx_" + table + "_" + c.name
+			}
+			add(name, false, c.name)
+		}
+		if name := c.tag["unique"]; name != "" {
+			add(name, true, c.name)
+		}
+	}
+	sort.Strings(names)
+	idxs := make([]index, 0, len(names))
+	for _, name := range names {
+		idxs = append(idxs, *byName[name])
+	}
+	return idxs
+}
+
+func columnDef(c column, d Dialect, pk bool) (string, error) {
+	t, ok := c.tag["type"]
+	auto := false
+	if !ok {
+		var err error
+		t, err = columnType(c, d)
+		if err != nil {
+			return "", err
+		}
+		if pk && isIntKind(fieldType(c.field)) {
+			auto = true
+			switch d {
+			case Postgres:
+				if fieldType(c.field).Size() < 8 {
+					t = "serial"
+				} else {
+					t = "bigserial"
+				}
+			case SQLite:
+				t = "INTEGER"
+			}
+		}
+	}
+
+	def := d.quote(c.name) + " " + t
+	if pk {
+		switch {
+		case auto && d == MySQL:
+			def += " NOT NULL AUTO_INCREMENT PRIMARY KEY"
+		case auto && d == SQLite:
+			def += " PRIMARY KEY AUTOINCREMENT"
+		default:
+			def += " PRIMARY KEY"
+		}
+		return def, nil
+	}
+	if _, ok := c.tag["notnull"]; ok {
+		def += " NOT NULL"
+	}
+	if v, ok := c.tag["unique"]; ok && v == "" {
+		def += " UNIQUE"
+	}
+	if v := c.tag["default"]; v != "" {
+		def += " DEFAULT " + v
+	}
+	return def, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// fieldType returns the Go type stored in the column, unwrapping pointers
+// and sql.Null* style structs.
+func fieldType(f *structs.Field) reflect.Type {
+	t := reflect.TypeOf(f.Value())
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && t != timeType && t.NumField() == 2 {
+		if v, ok := t.FieldByName("Valid"); ok && v.Type.Kind() == reflect.Bool {
+			for i := 0; i < t.NumField(); i++ {
+				if t.Field(i).Name != "Valid" {
+					return t.Field(i).Type
+				}
+			}
+		}
+	}
+	return t
+}
+
+func isIntKind(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func columnType(c column, d Dialect) (string, error) {
+	t := fieldType(c.field)
+	if t == timeType {
+		if d == Postgres {
+			return "timestamp", nil
+		}
+		return "datetime", nil
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		switch d {
+		case Postgres:
+			return "bytea", nil
+		case MySQL:
+			return "longblob", nil
+		}
+		return "blob", nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		switch d {
+		case MySQL:
+			return "tinyint(1)", nil
+		case SQLite:
+			return "integer", nil
+		}
+		return "boolean", nil
+	case reflect.Int8, reflect.Uint8:
+		if d == MySQL {
+			return "tinyint", nil
+		}
+		return "smallint", nil
+	case reflect.Int16, reflect.Uint16:
+		return "smallint", nil
+	case reflect.Int32, reflect.Uint32:
+		return "integer", nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return "bigint", nil
+	case reflect.Float32:
+		return "real", nil
+	case reflect.Float64:
+		if d == Postgres {
+			return "double precision", nil
+		}
+		return "double", nil
+	case reflect.String:
+		size := c.tag["size"]
+		if size == "" {
+			size = "255"
+		}
+		if d == SQLite {
+			return "text", nil
+		}
+		return "varchar(" + size + ")", nil
+	}
+	return "", fmt.Errorf("unsupport type %s of field %s, set it with type tag", t, c.field.Name())
+}
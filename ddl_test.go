@@ -0,0 +1,78 @@
+package sqlxx
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+type Product struct {
+	Id        int64          `db:"id"`
+	Sku       string         `db:"sku" sqlxx:"size:32;notnull;unique"`
+	Name      string         `db:"name" sqlxx:"size:100;index:idx_product_name_state"`
+	State     int8           `db:"state" sqlxx:"notnull;default:1;index:idx_product_name_state"`
+	Price     float64        `db:"price" sqlxx:"type:decimal(10,2)"`
+	Memo      sql.NullString `db:"memo"`
+	OnSale    bool           `db:"on_sale" sqlxx:"index"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+func TestCreateTableSQL(t *testing.T) {
+	s, err := CreateTableSQL(&Product{}, MySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "CREATE TABLE `product` (\n" +
+		"  `id` bigint NOT NULL AUTO_INCREMENT PRIMARY KEY,\n" +
+		"  `sku` varchar(32) NOT NULL UNIQUE,\n" +
+		"  `name` varchar(100),\n" +
+		"  `state` tinyint NOT NULL DEFAULT 1,\n" +
+		"  `price` decimal(10,2),\n" +
+		"  `memo` varchar(255),\n" +
+		"  `on_sale` tinyint(1),\n" +
+		"  `created_at` datetime\n" +
+		");\n" +
+		"CREATE INDEX `idx_product_name_state` ON `product` (`name`,`state`);\n" +
+		"CREATE INDEX `idx_product_on_sale` ON `product` (`on_sale`);"
+	if s != want {
+		t.Errorf("got\n%s\nwant\n%s", s, want)
+	}
+
+	s, err = CreateTableSQL(&Product{}, Postgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "CREATE TABLE \"product\" (\n" +
+		"  \"id\" bigserial PRIMARY KEY,\n" +
+		"  \"sku\" varchar(32) NOT NULL UNIQUE,\n" +
+		"  \"name\" varchar(100),\n" +
+		"  \"state\" smallint NOT NULL DEFAULT 1,\n" +
+		"  \"price\" decimal(10,2),\n" +
+		"  \"memo\" varchar(255),\n" +
+		"  \"on_sale\" boolean,\n" +
+		"  \"created_at\" timestamp\n" +
+		");\n" +
+		"CREATE INDEX \"idx_product_name_state\" ON \"product\" (\"name\",\"state\");\n" +
+		"CREATE INDEX \"idx_product_on_sale\" ON \"product\" (\"on_sale\");"
+	if s != want {
+		t.Errorf("got\n%s\nwant\n%s", s, want)
+	}
+
+	s, err = CreateTableSQL(&Product{}, SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "CREATE TABLE \"product\" (\n  \"id\" INTEGER PRIMARY KEY AUTOINCREMENT,\n"; s[:len(want)] != want {
+		t.Errorf("got\n%s", s)
+	}
+}
+
+type Visit struct {
+	Path string `db:"path"`
+}
+
+func TestCreateTableSQL_NoPk(t *testing.T) {
+	if _, err := CreateTableSQL(&Visit{}, MySQL); err == nil {
+		t.Error("expected an error for a model without a primary key")
+	}
+}
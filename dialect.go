@@ -0,0 +1,40 @@
+package sqlxx
+
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite3"
+)
+
+// DialectOf returns the dialect of a database/sql driver name, pgx gives
+// Postgres and sqlite gives SQLite.
+func DialectOf(driverName string) Dialect {
+	switch driverName {
+	case "mysql":
+		return MySQL
+	case "postgres", "pgx", "pq-timeouts", "cloudsqlpostgres":
+		return Postgres
+	case "sqlite3", "sqlite":
+		return SQLite
+	}
+	return Dialect(driverName)
+}
+
+// rowValues reports whether the dialect understands row value
+// comparisons such as (a, b) > (?, ?).
+func (d Dialect) rowValues() bool {
+	switch d {
+	case MySQL, Postgres, SQLite:
+		return true
+	}
+	return false
+}
+
+func (d Dialect) quote(name string) string {
+	if d == MySQL {
+		return "`" + name + "`"
+	}
+	return `"` + name + `"`
+}
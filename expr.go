@@ -0,0 +1,27 @@
+package sqlxx
+
+import "fmt"
+
+// Expression is a raw SQL fragment with ? placeholders, see Expr.
+type Expression struct {
+	sql  string
+	args []interface{}
+}
+
+// Expr is a raw SQL fragment with ? placeholders, usable wherever the
+// query builder takes a column.
+func Expr(sql string, args ...interface{}) Expression {
+	return Expression{sql: sql, args: args}
+}
+
+func fieldOf(field interface{}) (string, []interface{}, error) {
+	switch f := field.(type) {
+	case string:
+		return f, nil, nil
+	case Expression:
+		return f.sql, f.args, nil
+	case *windowFunc:
+		return f.String(), nil, nil
+	}
+	return "", nil, fmt.Errorf("unsupport field type %T", field)
+}
@@ -1,10 +1,11 @@
 module github.com/hoperuin/sqlxx
 
-go 1.12
+go 1.18
 
 require (
 	github.com/fatih/structs v1.1.0
 	github.com/go-sql-driver/mysql v1.4.0
 	github.com/jmoiron/sqlx v1.2.0
-	google.golang.org/appengine v1.5.0 // indirect
 )
+
+require google.golang.org/appengine v1.5.0 // indirect
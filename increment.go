@@ -0,0 +1,50 @@
+package sqlxx
+
+import (
+	"sort"
+)
+
+func (sqlxx *Sqlxx) Increment(id interface{}, column string, delta interface{}) (int64, error) {
+	return sqlxx.incr(id, map[string]interface{}{column: delta}, "+")
+}
+
+func (sqlxx *Sqlxx) Decrement(id interface{}, column string, delta interface{}) (int64, error) {
+	return sqlxx.incr(id, map[string]interface{}{column: delta}, "-")
+}
+
+func (sqlxx *Sqlxx) IncrementMap(id interface{}, deltas map[string]interface{}) (int64, error) {
+	return sqlxx.incr(id, deltas, "+")
+}
+
+func (sqlxx *Sqlxx) incr(id interface{}, deltas map[string]interface{}, op string) (int64, error) {
+	res, err := sqlxx.incrQuery(id, deltas, op).Exec()
+	if err != nil {
+		return -1, err
+	}
+	return res.RowsAffected()
+}
+
+// incrQuery adds each delta to its column in one UPDATE on the primary
+// key, bumping the version column and skipping soft deleted rows if the
+// model has them.
+func (sqlxx *Sqlxx) incrQuery(id interface{}, deltas map[string]interface{}, op string) *updateQuery {
+	cols := make([]string, 0, len(deltas))
+	for col := range deltas {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	pk, _ := getPkValue(sqlxx.s)
+	u := sqlxx.UpdateWhere()
+	for _, col := range cols {
+		u.Set(col, Expr(col+" "+op+" ?", deltas[col]))
+	}
+	if col := taggedColumn(sqlxx.s, "version"); col != "" {
+		u.Set(col, Expr(col+" + 1"))
+	}
+	u.Where(pk, Equal, id)
+	if col := taggedColumn(sqlxx.s, "softdelete"); col != "" {
+		u.Where(col, IsNull, nil)
+	}
+	return u
+}
@@ -0,0 +1,30 @@
+package sqlxx
+
+import (
+	"database/sql"
+	"testing"
+)
+
+type Goods struct {
+	Id        int           `db:"id"`
+	Stock     int           `db:"stock"`
+	Views     int           `db:"views"`
+	Version   int           `db:"version" sqlxx:"version"`
+	DeletedAt sql.NullInt64 `db:"deleted_at" sqlxx:"softdelete"`
+}
+
+func TestSqlxx_Increment(t *testing.T) {
+	goodsDao := New(&Goods{}, db())
+	u := goodsDao.incrQuery(1, map[string]interface{}{"views": 1, "stock": -2}, "+")
+	s, err := u.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "UPDATE goods SET stock = stock + ? ,views = views + ? ,version = version + 1  WHERE id = ?  AND deleted_at is null "
+	if s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	if len(u.q.whereValue) != 3 || u.q.whereValue[0] != -2 {
+		t.Errorf("unexpected args %v", u.q.whereValue)
+	}
+}
@@ -0,0 +1,409 @@
+package sqlxx
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"hash/fnv"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const migrationTable = "schema_migrations"
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type MigrationStatus struct {
+	Version int64
+	Name    string
+	Applied bool
+	// Modified reports whether the up file changed since it was applied
+	Modified bool
+}
+
+// Migrator applies the versioned migrations of a directory, files are
+// named like 0001_create_user.up.sql and 0001_create_user.down.sql.
+// Statements end with a semicolon, a "DELIMITER //" line changes it as in
+// the mysql client for procedure and trigger bodies. MySQL commits DDL
+// implicitly, so a migration failing there halfway stays half applied.
+type Migrator struct {
+	db         *sqlx.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// NewMigrator loads the migrations found at the root of fsys, pass
+// os.DirFS(dir) for a directory or an embed.FS (fs.Sub it if the files
+// are in a sub directory).
+func NewMigrator(db *sqlx.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		db:         db,
+		dialect:    DialectOf(db.DriverName()),
+		migrations: migrations,
+	}, nil
+}
+
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		m := migrationFile.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		b, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names %s and %s", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(b)
+			sum := sha256.Sum256(b)
+			mig.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			mig.Down = string(b)
+		}
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Checksum == "" {
+			return nil, fmt.Errorf("migration %d %s has no up file", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.run(ctx, func(applied map[int64]string) error {
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; !ok {
+				if err := m.apply(ctx, mig, true); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Down rolls back the last n applied migrations.
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	return m.run(ctx, func(applied map[int64]string) error {
+		for i := len(m.migrations) - 1; i >= 0 && n > 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if err := m.apply(ctx, mig, false); err != nil {
+				return err
+			}
+			n--
+		}
+		return nil
+	})
+}
+
+// To migrates up or down until version is the last applied one, 0 rolls
+// back everything.
+func (m *Migrator) To(ctx context.Context, version int64) error {
+	return m.run(ctx, func(applied map[int64]string) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; ok && mig.Version > version {
+				if err := m.apply(ctx, mig, false); err != nil {
+					return err
+				}
+			}
+		}
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; !ok && mig.Version <= version {
+				if err := m.apply(ctx, mig, true); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.createTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		sum, ok := applied[mig.Version]
+		status = append(status, MigrationStatus{
+			Version:  mig.Version,
+			Name:     mig.Name,
+			Applied:  ok,
+			Modified: ok && sum != mig.Checksum,
+		})
+	}
+	return status, nil
+}
+
+// run calls fn with the applied versions while holding the migration
+// lock, after checking no applied migration was modified since.
+func (m *Migrator) run(ctx context.Context, fn func(applied map[int64]string) error) error {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := m.createTable(ctx); err != nil {
+		return err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	for _, mig := range m.migrations {
+		if sum, ok := applied[mig.Version]; ok && sum != mig.Checksum {
+			return fmt.Errorf("migration %d %s was modified after it was applied", mig.Version, mig.Name)
+		}
+	}
+	return fn(applied)
+}
+
+// lock takes an advisory lock held by a single connection, so that
+// instances starting together do not run the same migrations. sqlite
+// has none, its writes are serialized by the database file lock.
+func (m *Migrator) lock(ctx context.Context) (func(), error) {
+	var lockSQL, unlockSQL string
+	var key interface{}
+	switch m.dialect {
+	case MySQL:
+		lockSQL, unlockSQL = "SELECT GET_LOCK(?, -1)", "SELECT RELEASE_LOCK(?)"
+		key = migrationTable
+	case Postgres:
+		lockSQL, unlockSQL = "SELECT pg_advisory_lock($1)", "SELECT pg_advisory_unlock($1)"
+		h := fnv.New64a()
+		h.Write([]byte(migrationTable))
+		key = int64(h.Sum64())
+	default:
+		return func() {}, nil
+	}
+	conn, err := m.db.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if m.dialect == MySQL {
+		// GET_LOCK returns 1 once locked, 0 or NULL on failure
+		var got sql.NullInt64
+		err = conn.QueryRowContext(ctx, lockSQL, key).Scan(&got)
+		if err == nil && got.Int64 != 1 {
+			err = fmt.Errorf("cannot get lock %s", migrationTable)
+		}
+	} else {
+		_, err = conn.ExecContext(ctx, lockSQL, key)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return func() {
+		conn.ExecContext(context.Background(), unlockSQL, key)
+		conn.Close()
+	}, nil
+}
+
+func (m *Migrator) createTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+migrationTable+" ("+
+		"version bigint NOT NULL PRIMARY KEY, "+
+		"name varchar(255) NOT NULL, "+
+		"checksum varchar(64) NOT NULL, "+
+		"applied_at timestamp DEFAULT CURRENT_TIMESTAMP)")
+	return err
+}
+
+// applied returns the checksum of the applied migrations by version.
+func (m *Migrator) applied(ctx context.Context) (map[int64]string, error) {
+	var rows []struct {
+		Version  int64  `db:"version"`
+		Checksum string `db:"checksum"`
+	}
+	if err := m.db.SelectContext(ctx, &rows, "SELECT version, checksum FROM "+migrationTable); err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]string, len(rows))
+	for _, r := range rows {
+		applied[r.Version] = r.Checksum
+	}
+	return applied, nil
+}
+
+// apply runs the up or down part of mig and records it, in a transaction
+// except on mysql where DDL commits implicitly anyway.
+func (m *Migrator) apply(ctx context.Context, mig Migration, up bool) (err error) {
+	body, record, args := mig.Up, "INSERT INTO "+migrationTable+" (version, name, checksum) VALUES (?, ?, ?)", []interface{}{mig.Version, mig.Name, mig.Checksum}
+	if !up {
+		if strings.TrimSpace(mig.Down) == "" {
+			return fmt.Errorf("migration %d %s has no down file", mig.Version, mig.Name)
+		}
+		body, record, args = mig.Down, "DELETE FROM "+migrationTable+" WHERE version = ?", []interface{}{mig.Version}
+	}
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("migration %d %s: %w", mig.Version, mig.Name, err)
+		}
+	}()
+
+	var e sqlx.ExecerContext = m.db
+	var tx *sqlx.Tx
+	if m.dialect != MySQL {
+		if tx, err = m.db.BeginTxx(ctx, nil); err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				tx.Rollback()
+			}
+		}()
+		e = tx
+	}
+	for _, stmt := range splitStatements(body) {
+		if _, err = e.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	if _, err = e.ExecContext(ctx, m.db.Rebind(record), args...); err != nil {
+		return err
+	}
+	if tx != nil {
+		return tx.Commit()
+	}
+	return nil
+}
+
+// splitStatements splits a migration file on the delimiter, a semicolon
+// unless changed by a DELIMITER line, outside of quotes, comments and
+// postgres dollar quoted bodies.
+func splitStatements(body string) []string {
+	delim := ";"
+	var stmts []string
+	var sb strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(sb.String()); s != "" {
+			stmts = append(stmts, s)
+		}
+		sb.Reset()
+	}
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if i == 0 || body[i-1] == '\n' {
+			if m := delimiterLine.FindStringSubmatch(body[i:]); m != nil {
+				flush()
+				delim = m[1]
+				i += len(m[0]) - 1
+				continue
+			}
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1
+			for j < len(body) {
+				if body[j] == '\\' && c != '`' {
+					j += 2
+					continue
+				}
+				if body[j] == c {
+					// a doubled quote is an escaped one
+					if j+1 < len(body) && body[j+1] == c {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			end := j + 1
+			if end > len(body) {
+				end = len(body)
+			}
+			sb.WriteString(body[i:end])
+			i = end - 1
+		case c == '-' && strings.HasPrefix(body[i:], "--"):
+			end := strings.IndexByte(body[i:], '\n')
+			if end < 0 {
+				end = len(body) - i
+			}
+			sb.WriteString(body[i : i+end])
+			i += end - 1
+		case c == '/' && strings.HasPrefix(body[i:], "/*"):
+			end := strings.Index(body[i+2:], "*/")
+			if end < 0 {
+				end = len(body) - i - 2
+			} else {
+				end += 2
+			}
+			sb.WriteString(body[i : i+2+end])
+			i += 2 + end - 1
+		case strings.HasPrefix(body[i:], delim):
+			flush()
+			i += len(delim) - 1
+		case c == '$':
+			tag := dollarTag.FindString(body[i:])
+			if tag == "" {
+				sb.WriteByte(c)
+				continue
+			}
+			end := strings.Index(body[i+len(tag):], tag)
+			if end < 0 {
+				end = len(body) - i - len(tag)
+			} else {
+				end += len(tag)
+			}
+			sb.WriteString(body[i : i+len(tag)+end])
+			i += len(tag) + end - 1
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	flush()
+	return stmts
+}
+
+var dollarTag = regexp.MustCompile(`^\$[A-Za-z_]*\$`)
+
+var delimiterLine = regexp.MustCompile(`^(?i:delimiter)[ \t]+(\S+)[ \t]*(\n|$)`)
@@ -0,0 +1,64 @@
+package sqlxx
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_email.up.sql":     {Data: []byte("ALTER TABLE user ADD COLUMN email varchar(64);")},
+		"0002_add_email.down.sql":   {Data: []byte("ALTER TABLE user DROP COLUMN email;")},
+		"0001_create_user.up.sql":   {Data: []byte("CREATE TABLE user (id bigint);")},
+		"0001_create_user.down.sql": {Data: []byte("DROP TABLE user;")},
+		"README.md":                 {Data: []byte("not a migration")},
+	}
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 || migrations[0].Version != 1 || migrations[1].Name != "add_email" {
+		t.Fatalf("got %+v", migrations)
+	}
+	if migrations[0].Down != "DROP TABLE user;" || len(migrations[0].Checksum) != 64 {
+		t.Errorf("got %+v", migrations[0])
+	}
+
+	fsys["0003_orphan.down.sql"] = &fstest.MapFile{Data: []byte("SELECT 1")}
+	if _, err := loadMigrations(fsys); err == nil {
+		t.Error("want error for a migration without up file")
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	body := `-- create; the table
+CREATE TABLE note (body text DEFAULT 'a;b', title varchar(10) DEFAULT 'it''s;');
+/* seed; data */
+INSERT INTO note (body) VALUES ("x;y");
+CREATE FUNCTION f() RETURNS trigger AS $$ BEGIN RETURN NEW; END; $$ LANGUAGE plpgsql;
+`
+	want := []string{
+		"-- create; the table\nCREATE TABLE note (body text DEFAULT 'a;b', title varchar(10) DEFAULT 'it''s;')",
+		"/* seed; data */\nINSERT INTO note (body) VALUES (\"x;y\")",
+		"CREATE FUNCTION f() RETURNS trigger AS $$ BEGIN RETURN NEW; END; $$ LANGUAGE plpgsql",
+	}
+	if got := splitStatements(body); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	body = `CREATE TABLE note (body text);
+DELIMITER //
+CREATE TRIGGER note_bi BEFORE INSERT ON note FOR EACH ROW BEGIN SET NEW.body = 'a;b'; END//
+DELIMITER ;
+INSERT INTO note (body) VALUES ('x');
+`
+	want = []string{
+		"CREATE TABLE note (body text)",
+		"CREATE TRIGGER note_bi BEFORE INSERT ON note FOR EACH ROW BEGIN SET NEW.body = 'a;b'; END",
+		"INSERT INTO note (body) VALUES ('x')",
+	}
+	if got := splitStatements(body); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
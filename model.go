@@ -0,0 +1,141 @@
+package sqlxx
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"github.com/fatih/structs"
+	"github.com/jmoiron/sqlx/reflectx"
+	"reflect"
+)
+
+type column struct {
+	field *structs.Field
+	tag   map[string]string
+	name  string
+	// path is where sqlx scans the column, it differs from name for
+	// columns of a prefixed nested struct
+	path string
+	// fields are the Go field names leading to the field from the model
+	fields []string
+	// nilParent is set for the columns of a nil nested struct pointer,
+	// they are selected but neither inserted nor updated
+	nilParent bool
+}
+
+func (c column) selectName() string {
+	return selectAs(c.name, c.path)
+}
+
+// mappedSelectName is selectName with the path the mapper m finds the
+// field of model type t at, the naming strategy of Open and the lower
+// case default of sqlx may disagree on untagged fields.
+func (c column) mappedSelectName(m *reflectx.Mapper, t reflect.Type) string {
+	fi := m.TypeMap(t).Tree
+	for _, name := range c.fields {
+		var next *reflectx.FieldInfo
+		for _, child := range fi.Children {
+			if child != nil && child.Field.Name == name {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return c.selectName()
+		}
+		fi = next
+	}
+	return selectAs(c.name, fi.Path)
+}
+
+func selectAs(name, path string) string {
+	if path == name {
+		return name
+	}
+	return name + ` AS "` + path + `"`
+}
+
+// columns flattens the fields of s into table columns: anonymous
+// embedded structs, by value or pointer, contribute their fields as is,
+// and named nested structs tagged with a prefix option contribute them
+// with the prefix prepended to each column name.
+func columns(s *structs.Struct) []column {
+	return appendColumns(nil, s, "", "", nil, false)
+}
+
+func appendColumns(cs []column, s *structs.Struct, prefix string, path string, fields []string, nilParent bool) []column {
+	for _, v := range s.Fields() {
+		tag := tagOptions(v)
+		if _, ok := tag["-"]; ok || v.Tag("db") == "-" {
+			continue
+		}
+		if v.IsExported() {
+			if nested, ok := nestedStruct(v); ok && v.IsEmbedded() && v.Tag("db") == "" {
+				cs = appendColumns(cs, nested, prefix, path, withField(fields, v.Name()), nilParent || isNilPtr(v))
+				continue
+			}
+			if p, ok := tag["prefix"]; ok {
+				nested, ok := nestedStruct(v)
+				if !ok {
+					panic("prefix must be set on struct field")
+				}
+				name := v.Tag("db")
+				if name == "" {
+					name = namingStrategy().ColumnName(v.Name())
+				}
+				cs = appendColumns(cs, nested, prefix+p, path+name+".", withField(fields, v.Name()), nilParent || isNilPtr(v))
+				continue
+			}
+		}
+		if !v.IsExported() {
+			continue
+		}
+		name := v.Tag("db")
+		if name == "" {
+			name = namingStrategy().ColumnName(v.Name())
+		}
+		cs = append(cs, column{
+			field:     v,
+			tag:       tag,
+			name:      prefix + name,
+			path:      path + name,
+			fields:    withField(fields, v.Name()),
+			nilParent: nilParent,
+		})
+	}
+	return cs
+}
+
+func withField(fields []string, name string) []string {
+	return append(append(make([]string, 0, len(fields)+1), fields...), name)
+}
+
+var (
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+func isNilPtr(v *structs.Field) bool {
+	rv := reflect.ValueOf(v.Value())
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// nestedStruct returns the struct held by a struct or pointer to struct
+// field, a zero one if the pointer is nil. Types that map to a single
+// column, such as time.Time or sql.NullString, are not nested structs.
+func nestedStruct(v *structs.Field) (*structs.Struct, bool) {
+	rv := reflect.ValueOf(v.Value())
+	t := rv.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	if t.Implements(valuerType) || reflect.PtrTo(t).Implements(scannerType) || t.PkgPath() == "time" {
+		return nil, false
+	}
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return structs.New(reflect.New(t).Interface()), true
+	}
+	return structs.New(rv.Interface()), true
+}
@@ -0,0 +1,63 @@
+package sqlxx
+
+import (
+	"context"
+	"database/sql"
+	"github.com/fatih/structs"
+	"testing"
+	"time"
+)
+
+type BaseModel struct {
+	Id        int       `db:"id"`
+	CreatedAt time.Time `db:"created_at" sqlxx:"insertonly"`
+}
+
+type Audit struct {
+	UpdatedBy string `db:"updated_by"`
+}
+
+type Address struct {
+	City   string `db:"city"`
+	Street string `db:"street"`
+}
+
+type Member struct {
+	BaseModel
+	*Audit
+	Name    string         `db:"name"`
+	Email   sql.NullString `db:"email"`
+	Address Address        `sqlxx:"prefix:addr_"`
+}
+
+func TestColumns_Flatten(t *testing.T) {
+	s, _ := buildInsert(context.Background(), &Member{Name: "abc"}, false, true)
+	if want := "INSERT INTO member(id,created_at,name,email,addr_city,addr_street) VALUES (?,?,?,?,?,?)"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+
+	s, values := buildUpdate(context.Background(), &Member{BaseModel: BaseModel{Id: 1}, Name: "abc"}, true, false)
+	if want := "UPDATE member SET id = ? ,name = ? WHERE id = ?"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	if len(values) != 2 {
+		t.Errorf("unexpected values %v", values)
+	}
+
+	s, _ = buildInsert(context.Background(), &Member{Audit: &Audit{UpdatedBy: "admin"}, Name: "abc"}, false, true)
+	if want := "INSERT INTO member(id,created_at,updated_by,name,email,addr_city,addr_street) VALUES (?,?,?,?,?,?,?)"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+
+	names, _, _ := setFieldNames(structs.New(&Member{}), opSelect, false, true)
+	if want := "updated_by"; names[2] != want {
+		t.Errorf("got %q, want %q", names[2], want)
+	}
+	if want := `addr_city AS "address.city"`; names[5] != want {
+		t.Errorf("got %q, want %q", names[5], want)
+	}
+
+	if pk, v := getPkValue(structs.New(&Member{BaseModel: BaseModel{Id: 3}})); pk != "id" || v != 3 {
+		t.Errorf("got pk %s %v", pk, v)
+	}
+}
@@ -0,0 +1,227 @@
+package sqlxx
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Queries is a registry of the SQL found in .sql files, each query
+// starts with a "-- name: CountByName" line and runs until the next one.
+type Queries struct {
+	fsys    fs.FS
+	reload  bool
+	mu      sync.RWMutex
+	queries map[string]string
+	modTime map[string]time.Time
+}
+
+// LoadQueries loads the .sql files of fsys and its sub directories, pass
+// os.DirFS(dir) for a directory or an embed.FS.
+func LoadQueries(fsys fs.FS) (*Queries, error) {
+	qs := &Queries{fsys: fsys}
+	if err := qs.load(); err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+// HotReload makes qs reload the files whenever one of them changed, meant
+// for development as every lookup stats the files. Files of an embed.FS
+// have no modification time and never reload.
+func (qs *Queries) HotReload() *Queries {
+	qs.reload = true
+	return qs
+}
+
+func (qs *Queries) load() error {
+	queries := map[string]string{}
+	modTime := map[string]time.Time{}
+	err := fs.WalkDir(qs.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".sql" {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		b, err := fs.ReadFile(qs.fsys, p)
+		if err != nil {
+			return err
+		}
+		modTime[p] = info.ModTime()
+		return parseQueries(p, string(b), queries)
+	})
+	if err != nil {
+		return err
+	}
+	qs.mu.Lock()
+	qs.queries, qs.modTime = queries, modTime
+	qs.mu.Unlock()
+	return nil
+}
+
+func parseQueries(file string, src string, queries map[string]string) error {
+	var name string
+	var body strings.Builder
+	add := func() error {
+		if name == "" {
+			return nil
+		}
+		if _, ok := queries[name]; ok {
+			return fmt.Errorf("query %s of %s already defined", name, file)
+		}
+		queries[name] = strings.TrimSuffix(strings.TrimSpace(body.String()), ";")
+		body.Reset()
+		return nil
+	}
+	sc := bufio.NewScanner(strings.NewReader(src))
+	for sc.Scan() {
+		line := sc.Text()
+		if n, ok := queryName(line); ok {
+			if err := add(); err != nil {
+				return err
+			}
+			name = n
+			continue
+		}
+		if name != "" {
+			body.WriteString(line)
+			body.WriteByte('\n')
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return add()
+}
+
+func queryName(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "--") {
+		return "", false
+	}
+	line = strings.TrimSpace(strings.TrimPrefix(line, "--"))
+	if !strings.HasPrefix(line, "name:") {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, "name:")), true
+}
+
+var errChanged = errors.New("changed")
+
+// changed reports whether a file was modified, added or removed since
+// the last load.
+func (qs *Queries) changed() bool {
+	n := 0
+	err := fs.WalkDir(qs.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".sql" {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		n++
+		qs.mu.RLock()
+		t, ok := qs.modTime[p]
+		qs.mu.RUnlock()
+		if !ok || !t.Equal(info.ModTime()) {
+			return errChanged
+		}
+		return nil
+	})
+	qs.mu.RLock()
+	defer qs.mu.RUnlock()
+	return err != nil || n != len(qs.modTime)
+}
+
+// Get returns the SQL of the query name.
+func (qs *Queries) Get(name string) (string, error) {
+	if qs.reload && qs.changed() {
+		if err := qs.load(); err != nil {
+			return "", err
+		}
+	}
+	qs.mu.RLock()
+	defer qs.mu.RUnlock()
+	q, ok := qs.queries[name]
+	if !ok {
+		return "", fmt.Errorf("query %s not found", name)
+	}
+	return q, nil
+}
+
+// WithQueries returns a shallow copy of sqlxx bound to the queries of qs.
+func (sqlxx *Sqlxx) WithQueries(qs *Queries) *Sqlxx {
+	c := *sqlxx
+	c.queries = qs
+	return &c
+}
+
+type namedQuery struct {
+	sqlxx *Sqlxx
+	name  string
+}
+
+// Named returns the query name of the Queries bound with WithQueries.
+func (sqlxx *Sqlxx) Named(name string) *namedQuery {
+	return &namedQuery{sqlxx: sqlxx, name: name}
+}
+
+func (n *namedQuery) sql(args []interface{}) (string, []interface{}, error) {
+	if n.sqlxx.queries == nil {
+		return "", nil, errors.New("no queries bound, use WithQueries")
+	}
+	q, err := n.sqlxx.queries.Get(n.name)
+	if err != nil {
+		return "", nil, err
+	}
+	if isTemplate(q) || isNamed(q, args) {
+		return n.sqlxx.render("named:"+n.name, q, args)
+	}
+	return n.sqlxx.db.Rebind(q), args, nil
+}
+
+// Get scans the first row into dest, the model of sqlxx if dest is nil.
+func (n *namedQuery) Get(dest interface{}, args ...interface{}) error {
+	q, args, err := n.sql(args)
+	if err != nil {
+		return err
+	}
+	if dest == nil {
+		dest = n.sqlxx.dest
+	}
+	if n.sqlxx.isTx {
+		return n.sqlxx.tx.GetContext(n.sqlxx.context(), dest, q, args...)
+	}
+	return n.sqlxx.db.GetContext(n.sqlxx.context(), dest, q, args...)
+}
+
+func (n *namedQuery) Select(dest interface{}, args ...interface{}) error {
+	q, args, err := n.sql(args)
+	if err != nil {
+		return err
+	}
+	if n.sqlxx.isTx {
+		return n.sqlxx.tx.SelectContext(n.sqlxx.context(), dest, q, args...)
+	}
+	return n.sqlxx.db.SelectContext(n.sqlxx.context(), dest, q, args...)
+}
+
+func (n *namedQuery) Exec(args ...interface{}) (sql.Result, error) {
+	q, args, err := n.sql(args)
+	if err != nil {
+		return nil, err
+	}
+	if n.sqlxx.isTx {
+		return n.sqlxx.tx.ExecContext(n.sqlxx.context(), q, args...)
+	}
+	return n.sqlxx.db.ExecContext(n.sqlxx.context(), q, args...)
+}
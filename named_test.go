@@ -0,0 +1,65 @@
+package sqlxx
+
+import (
+	"log"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestQueries(t *testing.T) {
+	fsys := fstest.MapFS{
+		"user.sql": {Data: []byte(`-- name: CountByName
+SELECT count(*) FROM user
+WHERE name = ?;
+
+-- name: ListByAge
+SELECT * FROM user WHERE age > ? ORDER BY id;
+`), ModTime: time.Unix(1, 0)},
+		"sub/goods.sql": {Data: []byte("--name:GoodsById\nSELECT * FROM goods WHERE id = ?\n"), ModTime: time.Unix(1, 0)},
+	}
+	qs, err := LoadQueries(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"CountByName": "SELECT count(*) FROM user\nWHERE name = ?",
+		"ListByAge":   "SELECT * FROM user WHERE age > ? ORDER BY id",
+		"GoodsById":   "SELECT * FROM goods WHERE id = ?",
+	} {
+		if got, err := qs.Get(name); err != nil || got != want {
+			t.Errorf("Get(%s) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+
+	fsys["user.sql"] = &fstest.MapFile{Data: []byte("-- name: CountByName\nSELECT count(*) FROM user"), ModTime: time.Unix(2, 0)}
+	if got, _ := qs.Get("ListByAge"); got == "" {
+		t.Error("queries reloaded without HotReload")
+	}
+	qs.HotReload()
+	if _, err := qs.Get("ListByAge"); err == nil {
+		t.Error("want error for a query removed from a reloaded file")
+	}
+	if got, _ := qs.Get("CountByName"); got != "SELECT count(*) FROM user" {
+		t.Errorf("got %q after reload", got)
+	}
+
+	fsys["dup.sql"] = &fstest.MapFile{Data: []byte("-- name: CountByName\nSELECT 1")}
+	if _, err := LoadQueries(fsys); err == nil {
+		t.Error("want error for a duplicated query name")
+	}
+}
+
+func TestSqlxx_Named(t *testing.T) {
+	qs, err := LoadQueries(fstest.MapFS{
+		"user.sql": {Data: []byte("-- name: CountByName\nSELECT count(*) FROM user WHERE name = ?\n")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c int
+	if err := userDao.WithQueries(qs).Named("CountByName").Get(&c, "abc"); err != nil {
+		t.Error(err)
+	}
+	log.Println(c)
+}
@@ -0,0 +1,145 @@
+package sqlxx
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// NamingStrategy derives table and column names for structs and fields
+// that do not name them with a table or db tag.
+type NamingStrategy interface {
+	TableName(structName string) string
+	ColumnName(fieldName string) string
+	JoinTableName(left, right string) string
+}
+
+var (
+	namingMu sync.RWMutex
+	naming   NamingStrategy = SnakeCase()
+)
+
+// SetNamingStrategy replaces the naming strategy used by every model,
+// it should be called before any New or Open.
+func SetNamingStrategy(ns NamingStrategy) {
+	namingMu.Lock()
+	naming = ns
+	namingMu.Unlock()
+}
+
+func namingStrategy() NamingStrategy {
+	namingMu.RLock()
+	defer namingMu.RUnlock()
+	return naming
+}
+
+// setMapper maps the untagged fields of db with the naming strategy, only
+// Open calls it so a DB built by the caller keeps its own Mapper.
+func setMapper(db *sqlx.DB) {
+	if db != nil {
+		db.Mapper = reflectx.NewMapperFunc("db", namingStrategy().ColumnName)
+	}
+}
+
+type snakeCase struct{}
+
+func SnakeCase() NamingStrategy {
+	return snakeCase{}
+}
+
+func (snakeCase) TableName(structName string) string {
+	return toSnakeCase(structName)
+}
+
+func (snakeCase) ColumnName(fieldName string) string {
+	return toSnakeCase(fieldName)
+}
+
+func (snakeCase) JoinTableName(left, right string) string {
+	return toSnakeCase(left) + "_" + toSnakeCase(right)
+}
+
+type plural struct {
+	NamingStrategy
+}
+
+// Plural pluralizes the table names of ns, user_info becomes user_infos.
+func Plural(ns NamingStrategy) NamingStrategy {
+	return plural{ns}
+}
+
+func (p plural) TableName(structName string) string {
+	return toPlural(p.NamingStrategy.TableName(structName))
+}
+
+func (p plural) JoinTableName(left, right string) string {
+	return toPlural(p.NamingStrategy.JoinTableName(left, right))
+}
+
+type tablePrefix struct {
+	NamingStrategy
+	prefix string
+}
+
+func TablePrefix(prefix string, ns NamingStrategy) NamingStrategy {
+	return tablePrefix{ns, prefix}
+}
+
+func (p tablePrefix) TableName(structName string) string {
+	return p.prefix + p.NamingStrategy.TableName(structName)
+}
+
+func (p tablePrefix) JoinTableName(left, right string) string {
+	return p.prefix + p.NamingStrategy.JoinTableName(left, right)
+}
+
+type upperCase struct {
+	NamingStrategy
+}
+
+func UpperCase(ns NamingStrategy) NamingStrategy {
+	return upperCase{ns}
+}
+
+func (u upperCase) TableName(structName string) string {
+	return strings.ToUpper(u.NamingStrategy.TableName(structName))
+}
+
+func (u upperCase) ColumnName(fieldName string) string {
+	return strings.ToUpper(u.NamingStrategy.ColumnName(fieldName))
+}
+
+func (u upperCase) JoinTableName(left, right string) string {
+	return strings.ToUpper(u.NamingStrategy.JoinTableName(left, right))
+}
+
+// toSnakeCase keeps acronyms together, HTTPStatus becomes http_status
+// and UserID becomes user_id.
+func toSnakeCase(name string) string {
+	rs := []rune(name)
+	var out []rune
+	for i, r := range rs {
+		if i != 0 && unicode.IsUpper(r) {
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				out = append(out, '_')
+			}
+		}
+		out = append(out, unicode.ToLower(r))
+	}
+	return string(out)
+}
+
+func toPlural(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsAny(name[len(name)-2:len(name)-1], "aeiou"):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "z"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
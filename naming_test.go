@@ -0,0 +1,50 @@
+package sqlxx
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"reflect"
+	"testing"
+)
+
+type HTTPLog struct {
+	Id         int
+	HTTPStatus int
+	UserID     string
+	RemoteAddr string `db:"ip"`
+}
+
+func TestNamingStrategy(t *testing.T) {
+	s, _ := buildInsert(context.Background(), &HTTPLog{}, false, false)
+	if want := "INSERT INTO http_log(http_status,user_id,ip) VALUES (?,?,?)"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+
+	SetNamingStrategy(UpperCase(TablePrefix("t_", Plural(SnakeCase()))))
+	defer SetNamingStrategy(SnakeCase())
+	s, _ = buildInsert(context.Background(), &HTTPLog{}, false, false)
+	if want := "INSERT INTO T_HTTP_LOGS(HTTP_STATUS,USER_ID,ip) VALUES (?,?,?)"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	if got := namingStrategy().JoinTableName("User", "Category"); got != "T_USER_CATEGORIES" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestNamingMapper(t *testing.T) {
+	db := sqlx.NewDb(&sql.DB{}, "mysql")
+	want := []string{"id", `http_status AS "httpstatus"`, `user_id AS "userid"`, "ip"}
+	if got := New(&HTTPLog{}, db).fieldNames; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := NewRepo[HTTPLog](db).fieldNames; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	setMapper(db)
+	want = []string{"id", "http_status", "user_id", "ip"}
+	if got := New(&HTTPLog{}, db).fieldNames; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
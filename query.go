@@ -2,7 +2,15 @@ package sqlxx
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
 	"github.com/jmoiron/sqlx"
+	"reflect"
+	"strconv"
+	"strings"
 )
 
 type condition int
@@ -33,27 +41,39 @@ const (
 	Between
 	NotBetween
 	None
+	// existsSub and notExistsSub mark the subqueries of WhereExists and
+	// WhereNotExists
+	existsSub
+	notExistsSub
+	// orGroup marks the groups of WhereOr, Where cannot be given it
+	orGroup
 )
 
 type sqlValue struct {
-	key   string
-	value interface{}
+	key     string
+	keyArgs []interface{}
+	value   interface{}
 	//between used
 	value2 interface{}
 	cond   condition
 	st     sqlType
+	// err is an unsupported field, reported by build
+	err error
 }
 
-func newSqlValue(field string, cond condition, value interface{}, st sqlType) sqlValue {
+func newSqlValue(field interface{}, cond condition, value interface{}, st sqlType) sqlValue {
+	key, keyArgs, err := fieldOf(field)
 	return sqlValue{
-		key:   field,
-		value: value,
-		cond:  cond,
-		st:    st,
+		key:     key,
+		keyArgs: keyArgs,
+		value:   value,
+		cond:    cond,
+		st:      st,
+		err:     err,
 	}
 }
 
-func newSelectSqlValue(field string) sqlValue {
+func newSelectSqlValue(field interface{}) sqlValue {
 	return newSqlValue(field, None, nil, Select)
 }
 
@@ -61,25 +81,27 @@ func newFromSqlValue(table string) sqlValue {
 	return newSqlValue(table, None, nil, From)
 }
 
-func newWhereSqlValue(field string, cond condition, value interface{}) sqlValue {
+func newWhereSqlValue(field interface{}, cond condition, value interface{}) sqlValue {
 	return newSqlValue(field, cond, value, Where)
 }
 
-func newOrderSqlValue(field string, desc string) sqlValue {
+func newOrderSqlValue(field interface{}, desc string) sqlValue {
 	return newSqlValue(field, None, desc, Order)
 }
 
-func newGroupSqlValue(field string) sqlValue {
+func newGroupSqlValue(field interface{}) sqlValue {
 	return newSqlValue(field, None, nil, Group)
 }
 
-func newHavingSqlValue(field string, cond condition, value interface{}) sqlValue {
+func newHavingSqlValue(field interface{}, cond condition, value interface{}) sqlValue {
 	return newSqlValue(field, cond, value, Having)
 }
 
 type query struct {
 	dest        interface{}
 	db          *sqlx.DB
+	tx          *sqlx.Tx
+	ctx         context.Context
 	selectNames []string
 	slt         []sqlValue
 	from        []sqlValue
@@ -88,6 +110,39 @@ type query struct {
 	group       []sqlValue
 	having      []sqlValue
 	whereValue  []interface{}
+	limit       int
+	after       string
+	before      string
+	distinct    bool
+	distinctOn  []string
+	compound    []compound
+	ctes        []cte
+	joins       []join
+	lock        string
+	lockWait    string
+	windows     []namedWindow
+}
+
+type namedWindow struct {
+	name string
+	spec *windowSpec
+}
+
+type cte struct {
+	name      string
+	q         *query
+	recursive *query
+}
+
+type join struct {
+	kind  string
+	table string
+	on    Expression
+}
+
+type compound struct {
+	op string
+	q  *query
 }
 
 func newQuery(dest interface{}, db *sqlx.DB, selectNames []string) *query {
@@ -105,6 +160,10 @@ func newQuery2(dest interface{}, db *sqlx.DB) *query {
 	}
 }
 
+func Cond() *query {
+	return newQuery2(nil, nil)
+}
+
 func (q *query) Select(field ...string) *query {
 	for _, f := range field {
 		q.slt = append(q.slt, newSelectSqlValue(f))
@@ -112,6 +171,14 @@ func (q *query) Select(field ...string) *query {
 	return q
 }
 
+// SelectExpr selects expressions built with Expr or window functions.
+func (q *query) SelectExpr(field ...interface{}) *query {
+	for _, f := range field {
+		q.slt = append(q.slt, newSelectSqlValue(f))
+	}
+	return q
+}
+
 func (q *query) SelectDefault() *query {
 	for _, f := range q.selectNames {
 		q.slt = append(q.slt, newSelectSqlValue(f))
@@ -126,19 +193,63 @@ func (q *query) From(table ...string) *query {
 	return q
 }
 
-func (q *query) Where(field string, cond condition, value interface{}) *query {
+func (q *query) FromSub(sub *query, alias string) *query {
+	q.from = append(q.from, newSqlValue(alias, None, sub, From))
+	return q
+}
+
+func (q *query) Join(table string, on string, args ...interface{}) *query {
+	q.joins = append(q.joins, join{"JOIN", table, Expr(on, args...)})
+	return q
+}
+
+func (q *query) LeftJoin(table string, on string, args ...interface{}) *query {
+	q.joins = append(q.joins, join{"LEFT JOIN", table, Expr(on, args...)})
+	return q
+}
+
+func (q *query) With(name string, sub *query) *query {
+	q.ctes = append(q.ctes, cte{name: name, q: sub})
+	return q
+}
+
+// WithRecursive adds a recursive CTE whose body is anchor UNION ALL
+// recursive; recursive may select from name itself.
+func (q *query) WithRecursive(name string, anchor *query, recursive *query) *query {
+	q.ctes = append(q.ctes, cte{name: name, q: anchor, recursive: recursive})
+	return q
+}
+
+func (q *query) Where(field interface{}, cond condition, value interface{}) *query {
 	q.where = append(q.where, newWhereSqlValue(field, cond, value))
 	return q
 }
 
-func (q *query) Between(field string, value interface{}, value2 interface{}) *query {
+func (q *query) WhereExists(sub *query) *query {
+	q.where = append(q.where, newWhereSqlValue("", existsSub, sub))
+	return q
+}
+
+func (q *query) WhereNotExists(sub *query) *query {
+	q.where = append(q.where, newWhereSqlValue("", notExistsSub, sub))
+	return q
+}
+
+// WhereOr adds the OR of groups, each group being the AND of the Where
+// conditions collected on a Cond.
+func (q *query) WhereOr(groups ...*query) *query {
+	q.where = append(q.where, newWhereSqlValue("", orGroup, groups))
+	return q
+}
+
+func (q *query) Between(field interface{}, value interface{}, value2 interface{}) *query {
 	sqlValue := newWhereSqlValue(field, Between, value)
 	sqlValue.value2 = value2
 	q.where = append(q.where, sqlValue)
 	return q
 }
 
-func (q *query) Order(field string, desc string) *query {
+func (q *query) Order(field interface{}, desc string) *query {
 	q.order = append(q.order, newOrderSqlValue(field, desc))
 	return q
 }
@@ -150,76 +261,383 @@ func (q *query) Group(field ...string) *query {
 	return q
 }
 
-func (q *query) Having(field string, cond condition, value interface{}) *query {
-	q.where = append(q.where, newHavingSqlValue(field, cond, value))
+func (q *query) GroupExpr(field ...interface{}) *query {
+	for _, g := range field {
+		q.group = append(q.group, newGroupSqlValue(g))
+	}
+	return q
+}
+
+func (q *query) Having(field interface{}, cond condition, value interface{}) *query {
+	q.having = append(q.having, newHavingSqlValue(field, cond, value))
+	return q
+}
+
+func (q *query) Distinct() *query {
+	q.distinct = true
+	return q
+}
+
+func (q *query) DistinctOn(field ...string) *query {
+	q.distinctOn = append(q.distinctOn, field...)
+	return q
+}
+
+func (q *query) Union(other *query) *query {
+	q.compound = append(q.compound, compound{"UNION", other})
+	return q
+}
+
+func (q *query) UnionAll(other *query) *query {
+	q.compound = append(q.compound, compound{"UNION ALL", other})
+	return q
+}
+
+func (q *query) Intersect(other *query) *query {
+	q.compound = append(q.compound, compound{"INTERSECT", other})
 	return q
 }
 
+func (q *query) Except(other *query) *query {
+	q.compound = append(q.compound, compound{"EXCEPT", other})
+	return q
+}
+
+func (q *query) Window(name string, w *windowSpec) *query {
+	q.windows = append(q.windows, namedWindow{name, w})
+	return q
+}
+
+func (q *query) Limit(n int) *query {
+	q.limit = n
+	return q
+}
+
+func (q *query) ForUpdate() *query {
+	q.lock = "UPDATE"
+	return q
+}
+
+func (q *query) ForShare() *query {
+	q.lock = "SHARE"
+	return q
+}
+
+func (q *query) SkipLocked() *query {
+	q.lockWait = "SKIP LOCKED"
+	return q
+}
+
+func (q *query) NoWait() *query {
+	q.lockWait = "NOWAIT"
+	return q
+}
+
+func (q *query) dialect() Dialect {
+	if q.db == nil {
+		return ""
+	}
+	return DialectOf(q.db.DriverName())
+}
+
+func (q *query) writeSub(sb *bytes.Buffer, sub *query) error {
+	sql, err := sub.build()
+	if err != nil {
+		return err
+	}
+	sb.WriteString("(")
+	sb.WriteString(sql)
+	sb.WriteString(")")
+	q.whereValue = append(q.whereValue, sub.whereValue...)
+	return nil
+}
+
+func (q *query) writeKey(sb *bytes.Buffer, v sqlValue) error {
+	if v.err != nil {
+		return v.err
+	}
+	sb.WriteString(v.key)
+	q.whereValue = append(q.whereValue, v.keyArgs...)
+	return nil
+}
+
+func (q *query) writeConditions(sb *bytes.Buffer, conds []sqlValue) error {
+	for i, c := range conds {
+		if err := q.writeCondition(sb, c); err != nil {
+			return err
+		}
+		if i != len(conds)-1 {
+			sb.WriteString(" AND ")
+		}
+	}
+	return nil
+}
+
+func (q *query) writeCondition(sb *bytes.Buffer, v sqlValue) error {
+	switch v.cond {
+	case orGroup:
+		if !orHasCondition(v) {
+			return errors.New("where or without condition")
+		}
+		sb.WriteString("(")
+		for i, g := range v.value.([]*query) {
+			if i > 0 {
+				sb.WriteString(" OR ")
+			}
+			sb.WriteString("(")
+			if err := q.writeConditions(sb, g.where); err != nil {
+				return err
+			}
+			sb.WriteString(")")
+		}
+		sb.WriteString(") ")
+		return nil
+	case existsSub, notExistsSub:
+		if v.cond == notExistsSub {
+			sb.WriteString("not ")
+		}
+		sb.WriteString("exists ")
+		if err := q.writeSub(sb, v.value.(*query)); err != nil {
+			return err
+		}
+		sb.WriteString(" ")
+		return nil
+	}
+
+	if v.cond == None {
+		sb.WriteString("(")
+		if err := q.writeKey(sb, v); err != nil {
+			return err
+		}
+		sb.WriteString(") ")
+		return nil
+	}
+
+	if err := q.writeKey(sb, v); err != nil {
+		return err
+	}
+	switch v.cond {
+	case NotNull:
+		sb.WriteString(" is not null ")
+		return nil
+	case IsNull:
+		sb.WriteString(" is null ")
+		return nil
+	case Between:
+		sb.WriteString(" between ? AND ? ")
+		q.whereValue = append(q.whereValue, v.value, v.value2)
+		return nil
+	case NotBetween:
+		sb.WriteString(" not between ? AND ? ")
+		q.whereValue = append(q.whereValue, v.value, v.value2)
+		return nil
+	}
+
+	switch v.cond {
+	case Equal:
+		sb.WriteString(" = ")
+	case NotEqual:
+		sb.WriteString(" <> ")
+	case LessThanOrEqual:
+		sb.WriteString(" <= ")
+	case GreaterThanOrEqual:
+		sb.WriteString(" >= ")
+	case LessThan:
+		sb.WriteString(" < ")
+	case GreaterThan:
+		sb.WriteString(" > ")
+	case Like:
+		sb.WriteString(" like ")
+	case NotLike:
+		sb.WriteString(" not like ")
+	case In:
+		sb.WriteString(" in ")
+	case NotIn:
+		sb.WriteString(" not in ")
+	default:
+		panic("unsupport condition!")
+	}
+	if sub, ok := v.value.(*query); ok {
+		if err := q.writeSub(sb, sub); err != nil {
+			return err
+		}
+		sb.WriteString(" ")
+		return nil
+	}
+	if e, ok := v.value.(Expression); ok {
+		sb.WriteString(e.sql)
+		sb.WriteString(" ")
+		q.whereValue = append(q.whereValue, e.args...)
+		return nil
+	}
+	if v.cond == In || v.cond == NotIn {
+		if values, ok := inValues(v.value); ok {
+			if len(values) == 0 {
+				return errors.New("in condition of " + v.key + " without values")
+			}
+			sb.WriteString("(?" + strings.Repeat(", ?", len(values)-1) + ") ")
+			q.whereValue = append(q.whereValue, values...)
+			return nil
+		}
+	}
+	sb.WriteString("? ")
+	q.whereValue = append(q.whereValue, v.value)
+	return nil
+}
+
+// inValues returns the elements of a slice or array value, []byte and
+// driver.Valuer values being single values.
+func inValues(value interface{}) ([]interface{}, bool) {
+	if _, ok := value.(driver.Valuer); ok {
+		return nil, false
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	values := make([]interface{}, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values, true
+}
+
+func orHasCondition(v sqlValue) bool {
+	groups := v.value.([]*query)
+	for _, g := range groups {
+		if len(g.where) == 0 {
+			return false
+		}
+	}
+	return len(groups) > 0
+}
+
+// hasCondition reports whether conds restrict the rows, an empty WhereOr
+// does not.
+func hasCondition(conds []sqlValue) bool {
+	for _, c := range conds {
+		if c.cond != orGroup || orHasCondition(c) {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *query) writeWith(sb *bytes.Buffer) error {
+	if len(q.ctes) == 0 {
+		return nil
+	}
+	sb.WriteString("WITH ")
+	for _, c := range q.ctes {
+		if c.recursive != nil {
+			sb.WriteString("RECURSIVE ")
+			break
+		}
+	}
+	for i, c := range q.ctes {
+		sb.WriteString(c.name)
+		sb.WriteString(" AS (")
+		sql, err := c.q.build()
+		if err != nil {
+			return err
+		}
+		sb.WriteString(sql)
+		q.whereValue = append(q.whereValue, c.q.whereValue...)
+		if c.recursive != nil {
+			sql, err := c.recursive.build()
+			if err != nil {
+				return err
+			}
+			sb.WriteString(" UNION ALL ")
+			sb.WriteString(sql)
+			q.whereValue = append(q.whereValue, c.recursive.whereValue...)
+		}
+		sb.WriteString(")")
+		if i != len(q.ctes)-1 {
+			sb.WriteString(",")
+		}
+	}
+	sb.WriteString(" ")
+	return nil
+}
+
 func (q *query) build() (string, error) {
+	q.whereValue = nil
+	keyset, keysetValues, err := q.keyset()
+	if err != nil {
+		return "", err
+	}
+
+	if err := q.checkLock(); err != nil {
+		return "", err
+	}
+
 	var sb bytes.Buffer
+	if err := q.writeWith(&sb); err != nil {
+		return "", err
+	}
 	sb.WriteString("SELECT ")
+	if len(q.distinctOn) > 0 {
+		if q.dialect() != Postgres {
+			return "", errors.New("distinct on only supported by postgres")
+		}
+		sb.WriteString("DISTINCT ON (")
+		sb.WriteString(strings.Join(q.distinctOn, ","))
+		sb.WriteString(") ")
+	} else if q.distinct {
+		sb.WriteString("DISTINCT ")
+	}
 	for i, s := range q.slt {
-		sb.WriteString(s.key)
+		if err := q.writeKey(&sb, s); err != nil {
+			return "", err
+		}
 		if i != len(q.slt)-1 {
 			sb.WriteString(",")
 		}
 	}
 	sb.WriteString(" FROM ")
 	for i, f := range q.from {
+		if sub, ok := f.value.(*query); ok {
+			if err := q.writeSub(&sb, sub); err != nil {
+				return "", err
+			}
+			sb.WriteString(" ")
+		}
 		sb.WriteString(f.key)
 		if i != len(q.from)-1 {
 			sb.WriteString(",")
 		}
 	}
-	sb.WriteString(" WHERE ")
-	for i, w := range q.where {
-		q.whereValue = append(q.whereValue, w.value)
-		if w.cond == Between {
-			q.whereValue = append(q.whereValue, w.value2)
-		}
-		sb.WriteString(w.key)
-		switch w.cond {
-		case Equal:
-			sb.WriteString(" = ? ")
-		case NotEqual:
-			sb.WriteString(" <> ? ")
-		case LessThanOrEqual:
-			sb.WriteString(" <= ? ")
-		case GreaterThanOrEqual:
-			sb.WriteString(" >= ? ")
-		case LessThan:
-			sb.WriteString(" < ? ")
-		case GreaterThan:
-			sb.WriteString(" > ? ")
-		case NotNull:
-			sb.WriteString(" is not null ")
-		case IsNull:
-			sb.WriteString(" is null ")
-		case Like:
-			sb.WriteString(" like ? ")
-		case NotLike:
-			sb.WriteString(" not like ? ")
-		case In:
-			sb.WriteString(" in ? ")
-		case NotIn:
-			sb.WriteString(" not in ? ")
-		case Between:
-			sb.WriteString(" between ? AND ? ")
-		case NotBetween:
-			sb.WriteString(" not between ? AND ? ")
-		default:
-			panic("unsupport condition!")
+	for _, j := range q.joins {
+		sb.WriteString(" ")
+		sb.WriteString(j.kind)
+		sb.WriteString(" ")
+		sb.WriteString(j.table)
+		sb.WriteString(" ON ")
+		sb.WriteString(j.on.sql)
+		q.whereValue = append(q.whereValue, j.on.args...)
+	}
 
+	if len(q.where) > 0 || keyset != "" {
+		sb.WriteString(" WHERE ")
+		if err := q.writeConditions(&sb, q.where); err != nil {
+			return "", err
 		}
-		if i != len(q.from)-1 {
-			sb.WriteString(" AND ")
+		if keyset != "" {
+			if len(q.where) > 0 {
+				sb.WriteString(" AND ")
+			}
+			sb.WriteString(keyset)
+			q.whereValue = append(q.whereValue, keysetValues...)
 		}
 	}
 
 	if len(q.group) > 0 {
 		sb.WriteString(" GROUP BY ")
 		for i, g := range q.group {
-			sb.WriteString(g.key)
+			if err := q.writeKey(&sb, g); err != nil {
+				return "", err
+			}
 			if i != len(q.group)-1 {
 				sb.WriteString(",")
 			}
@@ -228,69 +646,126 @@ func (q *query) build() (string, error) {
 
 	if len(q.having) > 0 {
 		sb.WriteString(" HAVING ")
-		for i, h := range q.having {
-			q.whereValue = append(q.whereValue, h.value)
-			if h.cond == Between {
-				q.whereValue = append(q.whereValue, h.value2)
-			}
-			sb.WriteString(h.key)
-			switch h.cond {
-			case Equal:
-				sb.WriteString(" = ? ")
-			case NotEqual:
-				sb.WriteString(" <> ? ")
-			case LessThanOrEqual:
-				sb.WriteString(" <= ? ")
-			case GreaterThanOrEqual:
-				sb.WriteString(" >= ? ")
-			case LessThan:
-				sb.WriteString(" < ? ")
-			case GreaterThan:
-				sb.WriteString(" > ? ")
-			case NotNull:
-				sb.WriteString(" is not null ")
-			case IsNull:
-				sb.WriteString(" is null ")
-			case Like:
-				sb.WriteString(" like ? ")
-			case NotLike:
-				sb.WriteString(" not like ? ")
-			case In:
-				sb.WriteString(" in ? ")
-			case NotIn:
-				sb.WriteString(" not in ? ")
-			case Between:
-				sb.WriteString(" between ? AND ? ")
-			case NotBetween:
-				sb.WriteString(" not between ? AND ? ")
-			default:
-				panic("unsupport condition!")
+		if err := q.writeConditions(&sb, q.having); err != nil {
+			return "", err
+		}
+	}
 
+	if len(q.windows) > 0 {
+		sb.WriteString(" WINDOW ")
+		for i, w := range q.windows {
+			sb.WriteString(w.name)
+			sb.WriteString(" AS ")
+			sb.WriteString(w.spec.String())
+			if i != len(q.windows)-1 {
+				sb.WriteString(",")
 			}
-			if i != len(q.having)-1 {
-				sb.WriteString(" AND ")
+		}
+	}
+
+	for _, c := range q.compound {
+		sb.WriteString(" ")
+		sb.WriteString(c.op)
+		sb.WriteString(" ")
+		if q.dialect() == SQLite {
+			sub := c.q.strip()
+			sql, err := sub.build()
+			if err != nil {
+				return "", err
 			}
+			sb.WriteString(sql)
+			q.whereValue = append(q.whereValue, sub.whereValue...)
+		} else if err := q.writeSub(&sb, c.q); err != nil {
+			return "", err
 		}
 	}
 
 	if len(q.order) > 0 {
 		sb.WriteString(" ORDER BY ")
-		for _, o := range q.order {
-			sb.WriteString(o.key)
+		for i, o := range q.order {
+			if err := q.writeKey(&sb, o); err != nil {
+				return "", err
+			}
 			sb.WriteString(" ")
-			sb.WriteString(o.value.(string))
+			if q.before != "" {
+				sb.WriteString(reverseOrder(o.value.(string)))
+			} else {
+				sb.WriteString(o.value.(string))
+			}
+			if i != len(q.order)-1 {
+				sb.WriteString(",")
+			}
+		}
+	}
+
+	if q.limit > 0 {
+		sb.WriteString(" LIMIT ")
+		sb.WriteString(strconv.Itoa(q.limit))
+	}
+
+	if q.lock != "" {
+		sb.WriteString(" FOR ")
+		sb.WriteString(q.lock)
+		if q.lockWait != "" {
+			sb.WriteString(" ")
+			sb.WriteString(q.lockWait)
 		}
 	}
 
 	return sb.String(), nil
 }
 
+func (q *query) checkLock() error {
+	if q.lock == "" {
+		if q.lockWait != "" {
+			return errors.New("skip locked and nowait need ForUpdate or ForShare")
+		}
+		return nil
+	}
+	switch q.dialect() {
+	case SQLite:
+		return errors.New("row locking not supported by sqlite")
+	case Postgres:
+		if q.distinct || len(q.distinctOn) > 0 || len(q.group) > 0 || len(q.having) > 0 || len(q.compound) > 0 {
+			return errors.New("row locking not allowed with distinct, group by or union on postgres")
+		}
+	}
+	return nil
+}
+
+func (q *query) context() context.Context {
+	if q.ctx == nil {
+		return context.Background()
+	}
+	return q.ctx
+}
+
+func (q *query) get(dest interface{}, sql string, args []interface{}) error {
+	if q.tx != nil {
+		return q.tx.GetContext(q.context(), dest, q.tx.Rebind(sql), args...)
+	}
+	if q.lock != "" {
+		return errors.New("locking query must be run in Tx")
+	}
+	return q.db.GetContext(q.context(), dest, q.db.Rebind(sql), args...)
+}
+
+func (q *query) list(dest interface{}, sql string, args []interface{}) error {
+	if q.tx != nil {
+		return q.tx.SelectContext(q.context(), dest, q.tx.Rebind(sql), args...)
+	}
+	if q.lock != "" {
+		return errors.New("locking query must be run in Tx")
+	}
+	return q.db.SelectContext(q.context(), dest, q.db.Rebind(sql), args...)
+}
+
 func (q *query) Get() error {
 	sql, err := q.build()
 	if err != nil {
 		return err
 	}
-	err = q.db.Get(q.dest, sql, q.whereValue...)
+	err = q.get(q.dest, sql, q.whereValue)
 	return err
 }
 
@@ -299,7 +774,7 @@ func (q *query) Getx(dest interface{}) error {
 	if err != nil {
 		return err
 	}
-	err = q.db.Get(dest, sql, q.whereValue...)
+	err = q.get(dest, sql, q.whereValue)
 	return err
 }
 
@@ -308,6 +783,97 @@ func (q *query) List(dest interface{}) error {
 	if err != nil {
 		return err
 	}
-	err = q.db.Select(dest, sql, q.whereValue...)
+	err = q.list(dest, sql, q.whereValue)
+	return err
+}
+
+func (q *query) strip() *query {
+	c := *q
+	c.order = nil
+	c.limit = 0
+	c.after, c.before = "", ""
+	c.lock, c.lockWait = "", ""
+	return &c
+}
+
+func (q *query) aggregate(field interface{}) *query {
+	c := q.strip()
+	c.slt = []sqlValue{newSelectSqlValue(field)}
+	return c
+}
+
+func (q *query) countQuery() *query {
+	if len(q.group) > 0 || len(q.compound) > 0 || q.distinct || len(q.distinctOn) > 0 {
+		c := newQuery2(nil, q.db).Select("count(*)").FromSub(q.strip(), "t")
+		c.tx, c.ctx = q.tx, q.ctx
+		return c
+	}
+	return q.aggregate("count(*)")
+}
+
+func (q *query) Count() (int, error) {
+	var n int
+	err := q.countQuery().Getx(&n)
+	if err != nil {
+		return -1, err
+	}
+	return n, nil
+}
+
+// singleAggregate refuses the aggregates that would only read the first
+// group or the first part of a compound query.
+func (q *query) singleAggregate(fn string) error {
+	if len(q.group) > 0 || len(q.compound) > 0 {
+		return fmt.Errorf("%s of a grouped or compound query has a value per row, select it and use List", fn)
+	}
 	return nil
 }
+
+func (q *query) Sum(col string) (float64, error) {
+	if err := q.singleAggregate("SUM"); err != nil {
+		return 0, err
+	}
+	var f sql.NullFloat64
+	err := q.aggregate("SUM(" + col + ")").Getx(&f)
+	return f.Float64, err
+}
+
+func (q *query) Avg(col string) (float64, error) {
+	if err := q.singleAggregate("AVG"); err != nil {
+		return 0, err
+	}
+	var f sql.NullFloat64
+	err := q.aggregate("AVG(" + col + ")").Getx(&f)
+	return f.Float64, err
+}
+
+func (q *query) Min(col string, dest interface{}) error {
+	if err := q.singleAggregate("MIN"); err != nil {
+		return err
+	}
+	return q.aggregate("MIN(" + col + ")").Getx(dest)
+}
+
+func (q *query) Max(col string, dest interface{}) error {
+	if err := q.singleAggregate("MAX"); err != nil {
+		return err
+	}
+	return q.aggregate("MAX(" + col + ")").Getx(dest)
+}
+
+func (q *query) Pluck(col string, dest interface{}) error {
+	c := *q
+	c.slt = []sqlValue{newSelectSqlValue(col)}
+	return c.List(dest)
+}
+
+func (q *query) Exists() (bool, error) {
+	var b bool
+	c := q.strip()
+	sql, err := c.build()
+	if err != nil {
+		return false, err
+	}
+	err = q.get(&b, "SELECT EXISTS ("+sql+")", c.whereValue)
+	return b, err
+}
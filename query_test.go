@@ -1,7 +1,10 @@
 package sqlxx
 
 import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
 	"log"
+	"reflect"
 	"testing"
 )
 
@@ -24,3 +27,264 @@ func TestQuery_List(t *testing.T) {
 	}
 	log.Println(ul)
 }
+
+func TestQuery_After(t *testing.T) {
+	cursor, err := encodeCursor([]interface{}{"测试", 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := newQuery2(nil, sqlx.NewDb(&sql.DB{}, "mysql"))
+	q.Select("id", "name").From("user").Where("age", GreaterThan, 10).
+		Order("name", "asc").Order("id", "asc").After(cursor).Limit(20)
+	s, err := q.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT id,name FROM user WHERE age > ?  AND (name, id) > (?, ?) ORDER BY name asc,id asc LIMIT 20"
+	if s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	if len(q.whereValue) != 3 || q.whereValue[2] != int64(8) {
+		t.Errorf("unexpected args %v", q.whereValue)
+	}
+}
+
+func TestQuery_Before(t *testing.T) {
+	cursor, err := encodeCursor([]interface{}{"测试", 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := newQuery2(nil, sqlx.NewDb(&sql.DB{}, "mysql"))
+	q.Select("id", "name").From("user").Order("name", "desc").Order("id", "asc").Before(cursor).Limit(20)
+	s, err := q.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT id,name FROM user WHERE ((name > ?) OR (name = ? AND id < ?)) ORDER BY name asc,id desc LIMIT 20"
+	if s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
+
+func TestQuery_Subquery(t *testing.T) {
+	orders := newQuery2(nil, nil).Select("user_id").From("orders").Where("amount", GreaterThan, 100)
+	paid := newQuery2(nil, nil).Select("1").From("payment p").Where("p.user_id = u.id AND p.state", Equal, "paid")
+	recent := newQuery2(nil, nil).Select("id", "name").From("user").Where("age", LessThan, 30)
+	q := newQuery2(nil, nil).Select("u.id", "u.name").FromSub(recent, "u").
+		Where("u.name", Like, "abc%").Where("u.id", In, orders).WhereExists(paid)
+	s, err := q.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT u.id,u.name FROM (SELECT id,name FROM user WHERE age < ? ) u WHERE u.name like ?  AND u.id in (SELECT user_id FROM orders WHERE amount > ? )  AND exists (SELECT 1 FROM payment p WHERE p.user_id = u.id AND p.state = ? ) "
+	if s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	args := []interface{}{30, "abc%", 100, "paid"}
+	if len(q.whereValue) != len(args) {
+		t.Fatalf("got args %v, want %v", q.whereValue, args)
+	}
+	for i := range args {
+		if q.whereValue[i] != args[i] {
+			t.Errorf("got args %v, want %v", q.whereValue, args)
+		}
+	}
+}
+
+func TestQuery_Expr(t *testing.T) {
+	q := newQuery2(nil, sqlx.NewDb(&sql.DB{}, "postgres"))
+	q.Select("id").SelectExpr(Expr("COALESCE(email, ?) AS email", "none")).From("user").
+		Where(Expr("DATE(created_at)"), Equal, "2026-10-19").
+		Where(Expr("LOWER(name) LIKE ? OR age > ?", "abc%", 18), None, nil).
+		GroupExpr(Expr("DATE(created_at)")).
+		Having(Expr("count(*)"), GreaterThan, 1).
+		Order(Expr("FIELD(age, ?, ?)", 1, 2), "asc")
+	s, err := q.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT id,COALESCE(email, $1) AS email FROM user WHERE DATE(created_at) = $2  AND (LOWER(name) LIKE $3 OR age > $4)  GROUP BY DATE(created_at) HAVING count(*) > $5  ORDER BY FIELD(age, $6, $7) asc"
+	if s = q.db.Rebind(s); s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	args := []interface{}{"none", "2026-10-19", "abc%", 18, 1, 1, 2}
+	if len(q.whereValue) != len(args) {
+		t.Fatalf("got args %v, want %v", q.whereValue, args)
+	}
+	for i := range args {
+		if q.whereValue[i] != args[i] {
+			t.Errorf("got args %v, want %v", q.whereValue, args)
+		}
+	}
+}
+
+func TestQuery_Count(t *testing.T) {
+	q := newQuery2(nil, nil).Select("id", "name").From("user").Where("age", GreaterThan, 10).Order("id", "desc").Limit(10)
+	s, err := q.countQuery().build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT count(*) FROM user WHERE age > ? "; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+
+	q.Group("name")
+	s, err = q.countQuery().build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT count(*) FROM (SELECT id,name FROM user WHERE age > ?  GROUP BY name) t"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+
+	if _, err := q.Sum("age"); err == nil {
+		t.Error("want error for the sum of a grouped query")
+	}
+	var max int
+	if err := q.Max("age", &max); err == nil {
+		t.Error("want error for the max of a grouped query")
+	}
+}
+
+func TestQuery_Union(t *testing.T) {
+	db := sqlx.NewDb(&sql.DB{}, "mysql")
+	admins := newQuery2(nil, db).Select("id", "name").From("admin").Where("state", Equal, 1).Order("id", "asc")
+	q := newQuery2(nil, db).Distinct().Select("id", "name").From("user").Where("age", GreaterThan, 10).
+		UnionAll(admins).Order("name", "asc").Limit(10)
+	s, err := q.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT DISTINCT id,name FROM user WHERE age > ?  UNION ALL (SELECT id,name FROM admin WHERE state = ?  ORDER BY id asc) ORDER BY name asc LIMIT 10"
+	if s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+
+	q.db = sqlx.NewDb(&sql.DB{}, "sqlite3")
+	s, err = q.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "SELECT DISTINCT id,name FROM user WHERE age > ?  UNION ALL SELECT id,name FROM admin WHERE state = ?  ORDER BY name asc LIMIT 10"
+	if s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
+
+func TestQuery_UnionSQLite(t *testing.T) {
+	db := sqlx.NewDb(&sql.DB{}, "sqlite3")
+	admins := newQuery2(nil, db).Select("id", "name").From("admin").Where("state", Equal, 1)
+	q := newQuery2(nil, db).Select("id", "name").From("user").Where("age", GreaterThan, 10).UnionAll(admins)
+	s, err := q.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT id,name FROM user WHERE age > ?  UNION ALL SELECT id,name FROM admin WHERE state = ? "
+	if s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	if want := []interface{}{10, 1}; !reflect.DeepEqual(q.whereValue, want) {
+		t.Errorf("got args %v, want %v", q.whereValue, want)
+	}
+}
+
+func TestQuery_DistinctOn(t *testing.T) {
+	q := newQuery2(nil, sqlx.NewDb(&sql.DB{}, "postgres")).DistinctOn("name").Select("id", "name").From("user").Order("name", "asc")
+	s, err := q.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT DISTINCT ON (name) id,name FROM user ORDER BY name asc"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+
+	q.db = sqlx.NewDb(&sql.DB{}, "mysql")
+	if _, err := q.build(); err == nil {
+		t.Error("expected distinct on error for mysql")
+	}
+}
+
+func TestQuery_WithRecursive(t *testing.T) {
+	anchor := newQuery2(nil, nil).Select("id", "parent_id", "name").From("category").Where("id", Equal, 1)
+	recursive := newQuery2(nil, nil).Select("c.id", "c.parent_id", "c.name").From("category c").Join("tree t", "c.parent_id = t.id")
+	q := newQuery2(nil, nil).WithRecursive("tree", anchor, recursive).
+		Select("t.id", "t.name").From("tree t").LeftJoin("goods g", "g.category_id = t.id AND g.state = ?", 1).
+		Where("t.name", Like, "abc%")
+	s, err := q.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "WITH RECURSIVE tree AS (SELECT id,parent_id,name FROM category WHERE id = ?  UNION ALL SELECT c.id,c.parent_id,c.name FROM category c JOIN tree t ON c.parent_id = t.id) SELECT t.id,t.name FROM tree t LEFT JOIN goods g ON g.category_id = t.id AND g.state = ? WHERE t.name like ? "
+	if s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	args := []interface{}{1, 1, "abc%"}
+	for i := range args {
+		if len(q.whereValue) != len(args) || q.whereValue[i] != args[i] {
+			t.Fatalf("got args %v, want %v", q.whereValue, args)
+		}
+	}
+}
+
+func TestQuery_ForUpdate(t *testing.T) {
+	q := newQuery2(nil, sqlx.NewDb(&sql.DB{}, "postgres")).Select("id").From("job").
+		Where("state", Equal, 0).Order("id", "asc").Limit(1).ForUpdate().SkipLocked()
+	s, err := q.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT id FROM job WHERE state = ?  ORDER BY id asc LIMIT 1 FOR UPDATE SKIP LOCKED"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+
+	var id int
+	if err := q.Getx(&id); err == nil {
+		t.Error("expected error for locking query outside Tx")
+	}
+
+	q.db = sqlx.NewDb(&sql.DB{}, "sqlite3")
+	if _, err := q.build(); err == nil {
+		t.Error("expected error for locking query on sqlite")
+	}
+
+	q = newQuery2(nil, sqlx.NewDb(&sql.DB{}, "mysql")).Select("id").From("job").SkipLocked()
+	if _, err := q.build(); err == nil {
+		t.Error("expected error for skip locked without lock mode")
+	}
+
+	q = newQuery2(nil, sqlx.NewDb(&sql.DB{}, "postgres")).Select("state").From("job").Group("state").ForUpdate()
+	if _, err := q.build(); err == nil {
+		t.Error("expected error for locking a grouped query on postgres")
+	}
+	q.db = sqlx.NewDb(&sql.DB{}, "mysql")
+	if _, err := q.build(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQuery_UnsupportField(t *testing.T) {
+	q := newQuery2(nil, nil).Select("id").From("user").Where(42, Equal, 1)
+	if _, err := q.build(); err == nil || err.Error() != "unsupport field type int" {
+		t.Errorf("got %v", err)
+	}
+	q = newQuery2(nil, nil).SelectExpr([]string{"id"}).From("user")
+	if _, err := q.build(); err == nil {
+		t.Error("want error for an unsupported select field")
+	}
+}
+
+func TestQuery_Window(t *testing.T) {
+	q := newQuery2(nil, nil).Select("id", "name").SelectExpr(
+		RowNumber().Over(Window().PartitionBy("age").OrderBy("id", "desc")).As("rn"),
+		Sum("score").Over(Window().OrderBy("id", "asc").Rows(UnboundedPreceding, CurrentRow)).As("total"),
+		Rank().OverWindow("w").As("r"),
+	).From("user").Window("w", Window().PartitionBy("age").OrderBy("score", "desc"))
+	s, err := q.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT id,name,ROW_NUMBER() OVER (PARTITION BY age ORDER BY id desc) AS rn,SUM(score) OVER (ORDER BY id asc ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS total,RANK() OVER w AS r FROM user WINDOW w AS (PARTITION BY age ORDER BY score desc)"
+	if s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
@@ -0,0 +1,266 @@
+package sqlxx
+
+import (
+	"context"
+	"database/sql"
+	"github.com/fatih/structs"
+	"github.com/jmoiron/sqlx"
+	"reflect"
+)
+
+// Repo is the typed counterpart of Sqlxx, every call reads into or
+// writes from its own T instead of a shared dest.
+type Repo[T any] struct {
+	db         *sqlx.DB
+	tx         *sqlx.Tx
+	fieldNames []string
+}
+
+func NewRepo[T any](db *sqlx.DB) *Repo[T] {
+	var zero T
+	fieldNames, _, _ := setFieldNames(structs.New(&zero), opSelect, false, true, mappedBy(db, &zero))
+	return &Repo[T]{
+		db:         db,
+		fieldNames: fieldNames,
+	}
+}
+
+// WithTx returns a copy of r whose calls run in tx.
+func (r *Repo[T]) WithTx(tx *sqlx.Tx) *Repo[T] {
+	c := *r
+	c.tx = tx
+	return &c
+}
+
+func (r *Repo[T]) table(ctx context.Context) string {
+	var zero T
+	return setTableName(ctx, &zero, structs.New(&zero))
+}
+
+func (r *Repo[T]) exec(ctx context.Context, sqls string, args []interface{}) (sql.Result, error) {
+	if r.tx != nil {
+		return r.tx.ExecContext(ctx, r.tx.Rebind(sqls), args...)
+	}
+	return r.db.ExecContext(ctx, r.db.Rebind(sqls), args...)
+}
+
+func (r *Repo[T]) Query() *Query[T] {
+	q := newQuery(nil, r.db, r.fieldNames)
+	q.tx = r.tx
+	return &Query[T]{q: q.SelectDefault(), table: r.table}
+}
+
+func (r *Repo[T]) Find(ctx context.Context, id interface{}) (T, error) {
+	var zero T
+	s := structs.New(&zero)
+	pk, ok := findPkColumn(s)
+	if !ok {
+		return zero, noPkError(s)
+	}
+	return r.Query().Where(pk.name, Equal, id).First(ctx)
+}
+
+func (r *Repo[T]) List(ctx context.Context, q *Query[T]) ([]T, error) {
+	if q == nil {
+		q = r.Query()
+	}
+	return q.All(ctx)
+}
+
+// Insert saves v and, when its primary key is a zero integer, fills it
+// with the id generated by the database if the driver reports one. A
+// model without a primary key, e.g. a join table, is only saved.
+func (r *Repo[T]) Insert(ctx context.Context, v *T) error {
+	sqls, args := buildInsert(ctx, v, false, false)
+	res, err := r.exec(ctx, sqls, args)
+	if err != nil {
+		return err
+	}
+	c, ok := findPkColumn(structs.New(v))
+	if !ok || !c.field.IsZero() {
+		return nil
+	}
+	pk := c.field
+	switch pk.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if id, err := res.LastInsertId(); err == nil {
+			rv := reflect.New(reflect.TypeOf(pk.Value())).Elem()
+			rv.SetInt(id)
+			return pk.Set(rv.Interface())
+		}
+	}
+	return nil
+}
+
+func (r *Repo[T]) Update(ctx context.Context, v *T) error {
+	s := structs.New(v)
+	pk, ok := findPkColumn(s)
+	if !ok {
+		return noPkError(s)
+	}
+	sqls, args := buildUpdate(ctx, v, false, false)
+	_, err := r.exec(ctx, sqls, append(args, pk.field.Value()))
+	return err
+}
+
+func (r *Repo[T]) Delete(ctx context.Context, id interface{}) error {
+	var zero T
+	s := structs.New(&zero)
+	pk, ok := findPkColumn(s)
+	if !ok {
+		return noPkError(s)
+	}
+	_, err := r.exec(ctx, "DELETE FROM "+r.table(ctx)+" WHERE "+pk.name+" = ?", []interface{}{id})
+	return err
+}
+
+// Query is a query builder whose terminals return T values.
+type Query[T any] struct {
+	q     *query
+	table func(ctx context.Context) string
+}
+
+func (q *Query[T]) prepare(ctx context.Context) {
+	q.q.ctx = ctx
+	if len(q.q.from) == 0 {
+		q.q.From(q.table(ctx))
+	}
+}
+
+func (q *Query[T]) Select(field ...string) *Query[T] {
+	q.q.slt = nil
+	q.q.Select(field...)
+	return q
+}
+
+func (q *Query[T]) SelectExpr(field ...interface{}) *Query[T] {
+	q.q.slt = nil
+	q.q.SelectExpr(field...)
+	return q
+}
+
+func (q *Query[T]) Join(table string, on string, args ...interface{}) *Query[T] {
+	q.q.Join(table, on, args...)
+	return q
+}
+
+func (q *Query[T]) LeftJoin(table string, on string, args ...interface{}) *Query[T] {
+	q.q.LeftJoin(table, on, args...)
+	return q
+}
+
+func (q *Query[T]) Where(field interface{}, cond condition, value interface{}) *Query[T] {
+	q.q.Where(field, cond, value)
+	return q
+}
+
+func (q *Query[T]) WhereOr(groups ...*query) *Query[T] {
+	q.q.WhereOr(groups...)
+	return q
+}
+
+func (q *Query[T]) WhereExists(sub *query) *Query[T] {
+	q.q.WhereExists(sub)
+	return q
+}
+
+func (q *Query[T]) WhereNotExists(sub *query) *Query[T] {
+	q.q.WhereNotExists(sub)
+	return q
+}
+
+func (q *Query[T]) Between(field interface{}, value interface{}, value2 interface{}) *Query[T] {
+	q.q.Between(field, value, value2)
+	return q
+}
+
+func (q *Query[T]) Order(field interface{}, desc string) *Query[T] {
+	q.q.Order(field, desc)
+	return q
+}
+
+func (q *Query[T]) Group(field ...string) *Query[T] {
+	q.q.Group(field...)
+	return q
+}
+
+func (q *Query[T]) GroupExpr(field ...interface{}) *Query[T] {
+	q.q.GroupExpr(field...)
+	return q
+}
+
+func (q *Query[T]) Having(field interface{}, cond condition, value interface{}) *Query[T] {
+	q.q.Having(field, cond, value)
+	return q
+}
+
+func (q *Query[T]) Distinct() *Query[T] {
+	q.q.Distinct()
+	return q
+}
+
+func (q *Query[T]) Limit(n int) *Query[T] {
+	q.q.Limit(n)
+	return q
+}
+
+func (q *Query[T]) After(cursor string) *Query[T] {
+	q.q.After(cursor)
+	return q
+}
+
+func (q *Query[T]) Before(cursor string) *Query[T] {
+	q.q.Before(cursor)
+	return q
+}
+
+func (q *Query[T]) ForUpdate() *Query[T] {
+	q.q.ForUpdate()
+	return q
+}
+
+func (q *Query[T]) ForShare() *Query[T] {
+	q.q.ForShare()
+	return q
+}
+
+func (q *Query[T]) SkipLocked() *Query[T] {
+	q.q.SkipLocked()
+	return q
+}
+
+func (q *Query[T]) NoWait() *Query[T] {
+	q.q.NoWait()
+	return q
+}
+
+func (q *Query[T]) First(ctx context.Context) (T, error) {
+	var t T
+	q.prepare(ctx)
+	err := q.q.Getx(&t)
+	return t, err
+}
+
+func (q *Query[T]) All(ctx context.Context) ([]T, error) {
+	var ts []T
+	q.prepare(ctx)
+	err := q.q.List(&ts)
+	return ts, err
+}
+
+func (q *Query[T]) Page(ctx context.Context) ([]T, Cursors, error) {
+	var ts []T
+	q.prepare(ctx)
+	c, err := q.q.Page(&ts)
+	return ts, c, err
+}
+
+func (q *Query[T]) Count(ctx context.Context) (int, error) {
+	q.prepare(ctx)
+	return q.q.Count()
+}
+
+func (q *Query[T]) Exists(ctx context.Context) (bool, error) {
+	q.prepare(ctx)
+	return q.q.Exists()
+}
@@ -0,0 +1,41 @@
+package sqlxx
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRepo_Query(t *testing.T) {
+	r := NewRepo[UserInfo](db())
+	q := r.Query().Where("age", GreaterThan, 10).Order("id", "desc").Limit(10)
+	q.prepare(context.Background())
+	s, err := q.q.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT id,name,age,email,address FROM user WHERE age > ?  ORDER BY id desc LIMIT 10"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
+
+func TestRepo_Find(t *testing.T) {
+	u, err := NewRepo[UserInfo](db()).Find(context.Background(), 2)
+	if err != nil {
+		t.Error(err)
+	}
+	t.Log(u)
+}
+
+func TestRepo_NoPk(t *testing.T) {
+	r := NewRepo[Visit](db())
+	ctx := context.Background()
+	if _, err := r.Find(ctx, 1); err == nil {
+		t.Error("Find: want error for a model without a primary key")
+	}
+	if err := r.Update(ctx, &Visit{Path: "/"}); err == nil {
+		t.Error("Update: want error for a model without a primary key")
+	}
+	if err := r.Delete(ctx, 1); err == nil {
+		t.Error("Delete: want error for a model without a primary key")
+	}
+}
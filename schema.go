@@ -0,0 +1,131 @@
+package sqlxx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"strings"
+)
+
+type TableInfo struct {
+	Name    string
+	Columns []ColumnInfo
+}
+
+type ColumnInfo struct {
+	Name string
+	// Type is the lower cased database type, with its size and unsigned
+	// attribute when the database reports them, e.g. "int(10) unsigned"
+	Type          string
+	Nullable      bool
+	PrimaryKey    bool
+	AutoIncrement bool
+}
+
+// LoadSchema reads the tables of the current database or schema, ordered
+// by name, with their columns in table order.
+func LoadSchema(ctx context.Context, db *sqlx.DB) ([]TableInfo, error) {
+	d := DialectOf(db.DriverName())
+	var names []string
+	var err error
+	switch d {
+	case MySQL:
+		err = db.SelectContext(ctx, &names, "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' ORDER BY table_name")
+	case Postgres:
+		err = db.SelectContext(ctx, &names, "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_type = 'BASE TABLE' ORDER BY table_name")
+	case SQLite:
+		err = db.SelectContext(ctx, &names, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	default:
+		return nil, fmt.Errorf("unsupport dialect %s", d)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]TableInfo, 0, len(names))
+	for _, name := range names {
+		cs, err := loadColumns(ctx, db, d, name)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, TableInfo{Name: name, Columns: cs})
+	}
+	return tables, nil
+}
+
+// loadColumns returns the columns of table, none if it does not exist.
+func loadColumns(ctx context.Context, db *sqlx.DB, d Dialect, table string) ([]ColumnInfo, error) {
+	// information_schema column names are upper cased by mysql 8, alias them
+	var rows []struct {
+		Name     string `db:"name"`
+		Type     string `db:"type"`
+		Nullable string `db:"nullable"`
+		Key      string `db:"col_key"`
+		Extra    string `db:"extra"`
+	}
+	var err error
+	switch d {
+	case MySQL:
+		err = db.SelectContext(ctx, &rows, "SELECT column_name AS name, column_type AS type, is_nullable AS nullable, column_key AS col_key, extra AS extra "+
+			"FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position", table)
+	case Postgres:
+		err = db.SelectContext(ctx, &rows, "SELECT c.column_name AS name, c.data_type AS type, c.is_nullable AS nullable, "+
+			"CASE WHEN EXISTS (SELECT 1 FROM information_schema.table_constraints tc JOIN information_schema.key_column_usage k "+
+			"ON k.constraint_name = tc.constraint_name AND k.table_schema = tc.table_schema AND k.table_name = tc.table_name "+
+			"WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = c.table_schema AND tc.table_name = c.table_name AND k.column_name = c.column_name) "+
+			"THEN 'PRI' ELSE '' END AS col_key, "+
+			"CASE WHEN c.is_identity = 'YES' OR c.column_default LIKE 'nextval(%' THEN 'auto_increment' ELSE '' END AS extra "+
+			"FROM information_schema.columns c WHERE c.table_schema = current_schema() AND c.table_name = $1 ORDER BY c.ordinal_position", table)
+	case SQLite:
+		return sqliteColumns(ctx, db, table)
+	default:
+		return nil, fmt.Errorf("unsupport dialect %s", d)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cs := make([]ColumnInfo, 0, len(rows))
+	for _, r := range rows {
+		cs = append(cs, ColumnInfo{
+			Name:          r.Name,
+			Type:          strings.ToLower(r.Type),
+			Nullable:      r.Nullable == "YES",
+			PrimaryKey:    r.Key == "PRI",
+			AutoIncrement: strings.Contains(r.Extra, "auto_increment"),
+		})
+	}
+	return cs, nil
+}
+
+func sqliteColumns(ctx context.Context, db *sqlx.DB, table string) ([]ColumnInfo, error) {
+	var rows []struct {
+		Cid     int            `db:"cid"`
+		Name    string         `db:"name"`
+		Type    string         `db:"type"`
+		NotNull bool           `db:"notnull"`
+		Default sql.NullString `db:"dflt_value"`
+		Pk      int            `db:"pk"`
+	}
+	if err := db.SelectContext(ctx, &rows, "PRAGMA table_info("+SQLite.quote(table)+")"); err != nil {
+		return nil, err
+	}
+	pks := 0
+	for _, r := range rows {
+		if r.Pk > 0 {
+			pks++
+		}
+	}
+	cs := make([]ColumnInfo, 0, len(rows))
+	for _, r := range rows {
+		typ := strings.ToLower(r.Type)
+		cs = append(cs, ColumnInfo{
+			Name:       r.Name,
+			Type:       typ,
+			Nullable:   !r.NotNull && r.Pk == 0,
+			PrimaryKey: r.Pk > 0,
+			// a single INTEGER primary key aliases the rowid
+			AutoIncrement: r.Pk > 0 && pks == 1 && typ == "integer",
+		})
+	}
+	return cs, nil
+}
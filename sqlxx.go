@@ -2,12 +2,16 @@ package sqlxx
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
 	"github.com/fatih/structs"
 	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+	"reflect"
 	"strings"
-	"unicode"
 )
 
 type SelectOner interface {
@@ -42,8 +46,16 @@ type Mapper interface {
 	Counter
 }
 
+// Open opens a DB whose Mapper maps the untagged fields with the naming
+// strategy. A DB opened otherwise keeps the Mapper it was given, the
+// generated selects alias the columns to the names that Mapper expects.
 func Open(driverName, dataSourceName string) (*sqlx.DB, error) {
-	return sqlx.Open(driverName, dataSourceName)
+	db, err := sqlx.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	setMapper(db)
+	return db, nil
 }
 
 type sqlCache map[string]string
@@ -78,24 +90,76 @@ func setCache(dest interface{}, s *structs.Struct) sqlCache {
 }
 
 func getPkValue(s *structs.Struct) (pk string, value interface{}) {
-	for _, v := range s.Fields() {
-		if v.Tag("pk") != "" {
-			pk, value = v.Name(), v.Value()
-		} else if v.Name() == "Id" {
-			pk, value = v.Tag("db"), v.Value()
+	c := pkColumn(s)
+	return c.name, c.field.Value()
+}
+
+func pkColumn(s *structs.Struct) column {
+	pk, ok := findPkColumn(s)
+	if !ok {
+		panic("must be id set")
+	}
+	return pk
+}
+
+func noPkError(s *structs.Struct) error {
+	return fmt.Errorf("%s has no primary key, tag a field pk or name it Id", s.Name())
+}
+
+// findPkColumn returns the column tagged pk or else the Id field.
+func findPkColumn(s *structs.Struct) (pk column, ok bool) {
+	for _, c := range columns(s) {
+		if c.field.Tag("pk") != "" {
+			pk = c
+		} else if c.field.Name() == "Id" {
+			pk = c
 		}
 	}
-	if pk == "" {
-		panic("must be id set")
+	return pk, pk.field != nil
+}
+
+type fieldOp int
+
+const (
+	opInsert fieldOp = iota + 1
+	opUpdate
+	opSelect
+	opWhere
+)
+
+// tagOptions parses a sqlxx tag such as `sqlxx:"insertonly;default:now()"`
+// into option names and their values.
+func tagOptions(f *structs.Field) map[string]string {
+	options := make(map[string]string)
+	for _, o := range strings.Split(f.Tag("sqlxx"), ";") {
+		o = strings.TrimSpace(o)
+		if o == "" {
+			continue
+		}
+		if i := strings.Index(o, ":"); i >= 0 {
+			options[strings.TrimSpace(o[:i])] = strings.TrimSpace(o[i+1:])
+		} else {
+			options[o] = ""
+		}
 	}
-	return
+	return options
+}
+
+func taggedColumn(s *structs.Struct, option string) string {
+	for _, c := range columns(s) {
+		if _, ok := c.tag[option]; ok {
+			return c.name
+		}
+	}
+	return ""
 }
 
-func buildInsert(s *structs.Struct, notNull bool, allField bool) (string, []interface{}) {
-	n, v, values := setFieldNames(s, notNull, allField)
+func buildInsert(ctx context.Context, value interface{}, notNull bool, allField bool, opts ...ColumnOption) (string, []interface{}) {
+	s := structs.New(value)
+	n, v, values := setFieldNames(s, opInsert, notNull, allField, opts...)
 	var sb bytes.Buffer
 	sb.WriteString("INSERT INTO ")
-	sb.WriteString(setTableName(s))
+	sb.WriteString(setTableName(ctx, value, s))
 	sb.WriteString("(")
 	sb.WriteString(strings.Join(n, ","))
 	sb.WriteString(") VALUES (")
@@ -104,33 +168,36 @@ func buildInsert(s *structs.Struct, notNull bool, allField bool) (string, []inte
 	return sb.String(), values
 }
 
-func buildUpdate(s *structs.Struct, notNull bool, allField bool) (string, []interface{}) {
-	n, _, values := setFieldNames(s, notNull, allField)
+func buildUpdate(ctx context.Context, value interface{}, notNull bool, allField bool, opts ...ColumnOption) (string, []interface{}) {
+	s := structs.New(value)
+	n, _, values := setFieldNames(s, opUpdate, notNull, allField, opts...)
 	var sb bytes.Buffer
 	sb.WriteString("UPDATE ")
-	sb.WriteString(setTableName(s))
+	sb.WriteString(setTableName(ctx, value, s))
 	sb.WriteString(" SET ")
 	setField := strings.Join(n, " = ? ,")
 	sb.WriteString(setField + " = ?")
-	sb.WriteString(" WHERE id = ?")
+	pk, _ := getPkValue(s)
+	sb.WriteString(" WHERE " + pk + " = ?")
 	return sb.String(), values
 }
 
-func buildUpdatew(s *structs.Struct, w *structs.Struct, notNull bool, allField bool) (string, []interface{}) {
-	n, _, sv := setFieldNames(s, notNull, allField)
+func buildUpdatew(ctx context.Context, value interface{}, where interface{}, notNull bool, allField bool, opts ...ColumnOption) (string, []interface{}) {
+	s, w := structs.New(value), structs.New(where)
+	n, _, sv := setFieldNames(s, opUpdate, notNull, allField, opts...)
 	var sb bytes.Buffer
 	sb.WriteString("UPDATE ")
-	sb.WriteString(setTableName(s))
+	sb.WriteString(setTableName(ctx, value, s))
 	sb.WriteString(" SET ")
 	setField := strings.Join(n, " = ? ,")
 	sb.WriteString(setField + " = ?")
 	sb.WriteString(" WHERE ")
 
-	nv, _, values := setFieldNames(w, true, true)
+	nv, _, values := setFieldNames(w, opWhere, true, true)
 	if len(nv) == 1 {
 		sb.WriteString(nv[0])
 	} else {
-		whereField := strings.Join(nv, " = ? ,")
+		whereField := strings.Join(nv, " = ? AND ")
 		sb.WriteString(whereField)
 	}
 	sb.WriteString(" = ?")
@@ -138,33 +205,35 @@ func buildUpdatew(s *structs.Struct, w *structs.Struct, notNull bool, allField b
 	return sb.String(), sv
 }
 
-func buildSelect(s *structs.Struct, notNull bool, allField bool) (string, []interface{}) {
-	n, _, _ := setFieldNames(s, notNull, allField)
+func buildSelect(ctx context.Context, value interface{}, notNull bool, allField bool, opts ...ColumnOption) (string, []interface{}) {
+	s := structs.New(value)
+	n, _, _ := setFieldNames(s, opSelect, notNull, allField, opts...)
 	var sb bytes.Buffer
 	sb.WriteString("SELECT ")
 	sb.WriteString(strings.Join(n, ","))
 	sb.WriteString(" FROM ")
-	sb.WriteString(setTableName(s))
+	sb.WriteString(setTableName(ctx, value, s))
 	sb.WriteString(" WHERE ")
 
-	nv, _, values := setFieldNames(s, true, true)
+	nv, _, values := setFieldNames(s, opWhere, true, true)
 	if len(nv) == 1 {
 		sb.WriteString(nv[0])
 	} else {
-		whereField := strings.Join(nv, " = ? ,")
+		whereField := strings.Join(nv, " = ? AND ")
 		sb.WriteString(whereField)
 	}
 	sb.WriteString(" = ?")
 	return sb.String(), values
 }
 
-func buildDelete(s *structs.Struct) (string, []interface{}) {
+func buildDelete(ctx context.Context, value interface{}) (string, []interface{}) {
+	s := structs.New(value)
 	var sb bytes.Buffer
 	sb.WriteString("DELETE  FROM ")
-	sb.WriteString(setTableName(s))
+	sb.WriteString(setTableName(ctx, value, s))
 	sb.WriteString(" WHERE ")
 
-	nv, _, values := setFieldNames(s, true, true)
+	nv, _, values := setFieldNames(s, opWhere, true, true)
 	if len(nv) == 1 {
 		sb.WriteString(nv[0])
 	} else {
@@ -175,13 +244,14 @@ func buildDelete(s *structs.Struct) (string, []interface{}) {
 	return sb.String(), values
 }
 
-func buildCount(s *structs.Struct) (string, []interface{}) {
+func buildCount(ctx context.Context, value interface{}) (string, []interface{}) {
+	s := structs.New(value)
 	var sb bytes.Buffer
 	sb.WriteString("SELECT count(*)  FROM ")
-	sb.WriteString(setTableName(s))
+	sb.WriteString(setTableName(ctx, value, s))
 	sb.WriteString(" WHERE ")
 
-	nv, _, values := setFieldNames(s, true, true)
+	nv, _, values := setFieldNames(s, opWhere, true, true)
 	if len(nv) == 1 {
 		sb.WriteString(nv[0])
 	} else {
@@ -192,93 +262,187 @@ func buildCount(s *structs.Struct) (string, []interface{}) {
 	return sb.String(), values
 }
 
-func setFieldNames(s *structs.Struct, notNull bool, allField bool) (names []string, valuePlaceholders []string, values []interface{}) {
-	for _, v := range s.Fields() {
-		if v.Tag("db") == "" && v.Tag("table") == "" {
-			panic("must be exist db tag")
-		} else {
-			if !v.IsExported() {
-				continue
-			}
-			if notNull {
-				zeroValue := isZeroValue(v)
-				if !zeroValue {
-					names = append(names, v.Tag("db"))
-					valuePlaceholders = append(valuePlaceholders, "?")
-					values = append(values, v.Value())
-				}
-			} else {
-				if !allField && v.Tag("db") == "id" {
-					continue
-				}
-				names = append(names, v.Tag("db"))
-				valuePlaceholders = append(valuePlaceholders, "?")
-				values = append(values, v.Value())
-			}
+type ColumnOption func(*columnFilter)
+
+type columnFilter struct {
+	only map[string]bool
+	omit map[string]bool
+	// mapper and model alias the selected columns to where the mapper
+	// of the DB scans them
+	mapper *reflectx.Mapper
+	model  reflect.Type
+}
+
+// Only restricts a call to the given columns, zero values included.
+func Only(cols ...string) ColumnOption {
+	return func(f *columnFilter) {
+		if f.only == nil {
+			f.only = make(map[string]bool)
+		}
+		for _, c := range cols {
+			f.only[c] = true
 		}
 	}
-	return
 }
 
-func isZeroValue(v *structs.Field) bool {
-	zeroValue := false
-	switch val := v.Value().(type) {
-	case int, int8, int16, int32, int64:
-		if val == 0 {
-			zeroValue = true
+// mappedBy aliases the selected columns of model for the mapper of db.
+func mappedBy(db *sqlx.DB, model interface{}) ColumnOption {
+	return func(f *columnFilter) {
+		if db != nil && db.Mapper != nil {
+			f.mapper, f.model = db.Mapper, reflect.TypeOf(model)
 		}
-	case float32, float64:
-		if val == 0.0 {
-			zeroValue = true
+	}
+}
+
+func Omit(cols ...string) ColumnOption {
+	return func(f *columnFilter) {
+		if f.omit == nil {
+			f.omit = make(map[string]bool)
 		}
-	case string:
-		if val == "" || len(val) == 0 {
-			zeroValue = true
+		for _, c := range cols {
+			f.omit[c] = true
 		}
-	case sql.NullString:
-		if !val.Valid {
-			zeroValue = true
+	}
+}
+
+func setFieldNames(s *structs.Struct, op fieldOp, notNull bool, allField bool, opts ...ColumnOption) (names []string, valuePlaceholders []string, values []interface{}) {
+	var filter columnFilter
+	for _, opt := range opts {
+		opt(&filter)
+	}
+	for _, c := range columns(s) {
+		v := c.field
+		name := c.name
+		if op == opSelect {
+			if filter.mapper != nil {
+				name = c.mappedSelectName(filter.mapper, filter.model)
+			} else {
+				name = c.selectName()
+			}
 		}
-	case sql.NullInt64:
-		if !val.Valid {
-			zeroValue = true
+		if filter.omit[c.name] {
+			continue
 		}
-	case sql.NullBool:
-		if !val.Valid {
-			zeroValue = true
+		if c.nilParent && (op == opInsert || op == opUpdate) {
+			continue
 		}
-	case sql.NullFloat64:
-		if !val.Valid {
-			zeroValue = true
+		if !fieldWritable(v, c.tag, op) {
+			continue
+		}
+		if filter.only != nil {
+			if filter.only[c.name] {
+				names = append(names, name)
+				valuePlaceholders = append(valuePlaceholders, "?")
+				values = append(values, v.Value())
+			}
+			continue
+		}
+		if notNull {
+			zeroValue := isZeroValue(v)
+			if !zeroValue {
+				names = append(names, name)
+				valuePlaceholders = append(valuePlaceholders, "?")
+				values = append(values, v.Value())
+			}
+		} else {
+			if !allField && (c.name == "id" || v.Name() == "Id") {
+				continue
+			}
+			names = append(names, name)
+			valuePlaceholders = append(valuePlaceholders, "?")
+			values = append(values, v.Value())
 		}
 	}
-	return zeroValue
+	return
 }
 
-func setTableName(s *structs.Struct) string {
-	if f, ok := s.FieldOk("table"); ok {
-		if f.Tag("table") == "" {
-			panic("must table tag")
+// fieldWritable applies the readonly, insertonly, default and omitempty
+// tag options of a field to an insert or update.
+func fieldWritable(v *structs.Field, tag map[string]string, op fieldOp) bool {
+	if op != opInsert && op != opUpdate {
+		return true
+	}
+	if _, ok := tag["readonly"]; ok {
+		return false
+	}
+	if _, ok := tag["insertonly"]; ok && op == opUpdate {
+		return false
+	}
+	if _, ok := tag["default"]; ok && op == opInsert && isZeroValue(v) {
+		return false
+	}
+	if _, ok := tag["omitempty"]; ok && isZeroValue(v) {
+		return false
+	}
+	return true
+}
+
+// IsZeroer lets a field type decide whether it is empty, and so left out
+// by the NotNull variants and struct based conditions.
+type IsZeroer interface {
+	IsZero() bool
+}
+
+func isZeroValue(v *structs.Field) bool {
+	return isZero(v.Value())
+}
+
+func isZero(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	// a nil pointer is empty, IsZero would be called through it
+	if k := rv.Kind(); (k == reflect.Ptr || k == reflect.Interface) && rv.IsNil() {
+		return true
+	}
+	if z, ok := value.(IsZeroer); ok {
+		return z.IsZero()
+	}
+	if rv.Kind() != reflect.Ptr {
+		p := reflect.New(rv.Type())
+		p.Elem().Set(rv)
+		if z, ok := p.Interface().(IsZeroer); ok {
+			return z.IsZero()
 		}
-		return f.Tag("table")
 	}
-	return toTableName(s.Name())
+	if valuer, ok := value.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		return err == nil && dv == nil
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	case reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
 }
 
-func toTableName(structName string) string {
-	var rs []rune
-	for i, r := range structName {
-		if i != 0 && unicode.IsUpper(r) {
-			rs = append(rs, '_', unicode.ToLower(r))
-		} else {
-			if unicode.IsUpper(r) {
-				rs = append(rs, unicode.ToLower(r))
-			} else {
-				rs = append(rs, r)
-			}
+type TableNamer interface {
+	TableName() string
+}
+
+// ContextTableNamer names the table per call, e.g. a monthly partition
+// picked from a value carried by the context passed to WithContext.
+type ContextTableNamer interface {
+	TableNameContext(ctx context.Context) string
+}
+
+func setTableName(ctx context.Context, value interface{}, s *structs.Struct) string {
+	if v, ok := value.(ContextTableNamer); ok {
+		return v.TableNameContext(ctx)
+	}
+	if v, ok := value.(TableNamer); ok {
+		return v.TableName()
+	}
+	if f, ok := s.FieldOk("table"); ok {
+		if f.Tag("table") == "" {
+			panic("must table tag")
 		}
+		return f.Tag("table")
 	}
-	return string(rs)
+	return namingStrategy().TableName(s.Name())
 }
 
 type Sqlxx struct {
@@ -294,27 +458,72 @@ type Sqlxx struct {
 	s          *structs.Struct
 	isTx       bool
 	query      *query
+	ctx        context.Context
+	queries    *Queries
+	validate   bool
+}
+
+// New panics with the problems found by Validate when the ValidateMappers
+// option is given, use NewE to get them as an error.
+func New(dest interface{}, db *sqlx.DB, opts ...Option) *Sqlxx {
+	sqlxx, err := NewE(dest, db, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return sqlxx
 }
 
-func New(dest interface{}, db *sqlx.DB) *Sqlxx {
+// NewE is New returning the problems found by Validate when the
+// ValidateMappers option is given.
+func NewE(dest interface{}, db *sqlx.DB, opts ...Option) (*Sqlxx, error) {
 	s := structs.New(dest)
 	fields := s.Fields()
-	fieldNames, _, _ := setFieldNames(s, false, true)
+	fieldNames, _, _ := setFieldNames(s, opSelect, false, true, mappedBy(db, dest))
 
-	return &Sqlxx{
+	sqlxx := &Sqlxx{
 		dest:       dest,
 		db:         db,
-		tableName:  setTableName(s),
+		tableName:  setTableName(context.Background(), dest, s),
 		cache:      setCache(dest, s),
 		fields:     fields,
 		fieldNames: fieldNames,
 		fieldLen:   len(fieldNames),
 		s:          s,
 	}
+	for _, opt := range opts {
+		opt(sqlxx)
+	}
+	if sqlxx.validate {
+		if err := sqlxx.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return sqlxx, nil
+}
+
+// WithContext returns a shallow copy of sqlxx whose calls run with ctx.
+func (sqlxx *Sqlxx) WithContext(ctx context.Context) *Sqlxx {
+	c := *sqlxx
+	c.ctx = ctx
+	return &c
+}
+
+func (sqlxx *Sqlxx) context() context.Context {
+	if sqlxx.ctx == nil {
+		return context.Background()
+	}
+	return sqlxx.ctx
+}
+
+func (sqlxx *Sqlxx) table() string {
+	if _, ok := sqlxx.dest.(ContextTableNamer); ok {
+		return setTableName(sqlxx.context(), sqlxx.dest, sqlxx.s)
+	}
+	return sqlxx.tableName
 }
 
 func (sqlxx *Sqlxx) Begin() (*Sqlxx, error) {
-	tx, err := sqlxx.db.Beginx()
+	tx, err := sqlxx.db.BeginTxx(sqlxx.context(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -332,15 +541,27 @@ func (sqlxx *Sqlxx) Commit() error {
 	return err
 }
 
+func (sqlxx *Sqlxx) Rollback() error {
+	if sqlxx.tx == nil || sqlxx.isTx == false {
+		return errors.New("No start Tx")
+	}
+	sqlxx.isTx = false
+	err := sqlxx.tx.Rollback()
+	return err
+}
+
 func (sqlxx *Sqlxx) SelectOne(args ...interface{}) (interface{}, error) {
 	if _, ok := sqlxx.dest.(SelectOner); !ok {
 		return nil, errors.New("must be implement SelectOner interface")
 	}
-	var err error
+	sqls, args, err := sqlxx.render("selectOne", sqlxx.cache["selectOne"], args)
+	if err != nil {
+		return nil, err
+	}
 	if sqlxx.isTx {
-		err = sqlxx.tx.Get(sqlxx.dest, sqlxx.cache["selectOne"], args...)
+		err = sqlxx.tx.GetContext(sqlxx.context(), sqlxx.dest, sqls, args...)
 	} else {
-		err = sqlxx.db.Get(sqlxx.dest, sqlxx.cache["selectOne"], args...)
+		err = sqlxx.db.GetContext(sqlxx.context(), sqlxx.dest, sqls, args...)
 	}
 
 	if err != nil {
@@ -353,36 +574,37 @@ func (sqlxx *Sqlxx) Select(dest interface{}, args ...interface{}) error {
 	if _, ok := sqlxx.dest.(Selecter); !ok {
 		return errors.New("must be implement Selecter interface")
 	}
-	var err error
+	sqls, args, err := sqlxx.render("select", sqlxx.cache["select"], args)
+	if err != nil {
+		return err
+	}
 	if sqlxx.isTx {
-		err = sqlxx.tx.Select(dest, sqlxx.cache["select"], args...)
+		err = sqlxx.tx.SelectContext(sqlxx.context(), dest, sqls, args...)
 	} else {
-		err = sqlxx.db.Select(dest, sqlxx.cache["select"], args...)
+		err = sqlxx.db.SelectContext(sqlxx.context(), dest, sqls, args...)
 	}
 
 	return err
 }
 
-func (sqlxx *Sqlxx) SelectOnex(value interface{}) (interface{}, error) {
-	s := structs.New(value)
-	sql, args := buildSelect(s, false, true)
+func (sqlxx *Sqlxx) SelectOnex(value interface{}, opts ...ColumnOption) (interface{}, error) {
+	sql, args := buildSelect(sqlxx.context(), value, false, true, append(opts, mappedBy(sqlxx.db, value))...)
 	var err error
 	if sqlxx.isTx {
-		err = sqlxx.tx.Get(sqlxx.dest, sql, args...)
+		err = sqlxx.tx.GetContext(sqlxx.context(), sqlxx.dest, sql, args...)
 	} else {
-		err = sqlxx.db.Get(sqlxx.dest, sql, args...)
+		err = sqlxx.db.GetContext(sqlxx.context(), sqlxx.dest, sql, args...)
 	}
 	return sqlxx.dest, err
 }
 
-func (sqlxx *Sqlxx) Selectx(dest interface{}, value interface{}) error {
-	s := structs.New(value)
-	sql, args := buildSelect(s, false, true)
+func (sqlxx *Sqlxx) Selectx(dest interface{}, value interface{}, opts ...ColumnOption) error {
+	sql, args := buildSelect(sqlxx.context(), value, false, true, append(opts, mappedBy(sqlxx.db, value))...)
 	var err error
 	if sqlxx.isTx {
-		err = sqlxx.tx.Select(dest, sql, args...)
+		err = sqlxx.tx.SelectContext(sqlxx.context(), dest, sql, args...)
 	} else {
-		err = sqlxx.db.Select(dest, sql, args...)
+		err = sqlxx.db.SelectContext(sqlxx.context(), dest, sql, args...)
 	}
 	return err
 }
@@ -392,11 +614,14 @@ func (sqlxx *Sqlxx) Count(args ...interface{}) (int, error) {
 	if _, ok := sqlxx.dest.(Counter); !ok {
 		return -1, errors.New("must be implement Counter interface")
 	}
-	var err error
+	sqls, args, err := sqlxx.render("count", sqlxx.cache["count"], args)
+	if err != nil {
+		return -1, err
+	}
 	if sqlxx.isTx {
-		err = sqlxx.tx.Get(&c, sqlxx.cache["count"], args...)
+		err = sqlxx.tx.GetContext(sqlxx.context(), &c, sqls, args...)
 	} else {
-		err = sqlxx.db.Get(&c, sqlxx.cache["count"], args...)
+		err = sqlxx.db.GetContext(sqlxx.context(), &c, sqls, args...)
 	}
 
 	if err != nil {
@@ -407,13 +632,12 @@ func (sqlxx *Sqlxx) Count(args ...interface{}) (int, error) {
 
 func (sqlxx *Sqlxx) Countx(value interface{}) (int, error) {
 	var c int
-	s := structs.New(value)
-	sql, args := buildCount(s)
+	sql, args := buildCount(sqlxx.context(), value)
 	var err error
 	if sqlxx.isTx {
-		err = sqlxx.tx.Get(&c, sql, args...)
+		err = sqlxx.tx.GetContext(sqlxx.context(), &c, sql, args...)
 	} else {
-		err = sqlxx.db.Get(&c, sql, args...)
+		err = sqlxx.db.GetContext(sqlxx.context(), &c, sql, args...)
 	}
 	if err != nil {
 		return -1, err
@@ -425,12 +649,15 @@ func (sqlxx *Sqlxx) Update(args ...interface{}) (sql.Result, error) {
 	if _, ok := sqlxx.dest.(Updater); !ok {
 		return nil, errors.New("must be implement Updater interface")
 	}
-	var err error
+	sqls, args, err := sqlxx.render("update", sqlxx.cache["update"], args)
+	if err != nil {
+		return nil, err
+	}
 	var res sql.Result
 	if sqlxx.isTx {
-		res, err = sqlxx.tx.Exec(sqlxx.cache["update"], args...)
+		res, err = sqlxx.tx.ExecContext(sqlxx.context(), sqls, args...)
 	} else {
-		res, err = sqlxx.db.Exec(sqlxx.cache["update"], args...)
+		res, err = sqlxx.db.ExecContext(sqlxx.context(), sqls, args...)
 	}
 	if err != nil {
 		return nil, err
@@ -438,17 +665,17 @@ func (sqlxx *Sqlxx) Update(args ...interface{}) (sql.Result, error) {
 	return res, nil
 }
 
-func (sqlxx *Sqlxx) Updatex(value interface{}) (sql.Result, error) {
+func (sqlxx *Sqlxx) Updatex(value interface{}, opts ...ColumnOption) (sql.Result, error) {
 	s := structs.New(value)
-	sqls, values := buildUpdate(s, false, false)
+	sqls, values := buildUpdate(sqlxx.context(), value, false, false, opts...)
 	_, pkVal := getPkValue(s)
 	values = append(values, pkVal)
 	var err error
 	var res sql.Result
 	if sqlxx.isTx {
-		res, err = sqlxx.tx.Exec(sqls, values...)
+		res, err = sqlxx.tx.ExecContext(sqlxx.context(), sqls, values...)
 	} else {
-		res, err = sqlxx.db.Exec(sqls, values...)
+		res, err = sqlxx.db.ExecContext(sqlxx.context(), sqls, values...)
 	}
 	if err != nil {
 		return nil, err
@@ -456,17 +683,17 @@ func (sqlxx *Sqlxx) Updatex(value interface{}) (sql.Result, error) {
 	return res, nil
 }
 
-func (sqlxx *Sqlxx) UpdatexNotNull(value interface{}) (sql.Result, error) {
+func (sqlxx *Sqlxx) UpdatexNotNull(value interface{}, opts ...ColumnOption) (sql.Result, error) {
 	s := structs.New(value)
-	sqls, values := buildUpdate(s, true, false)
+	sqls, values := buildUpdate(sqlxx.context(), value, true, false, opts...)
 	_, pkVal := getPkValue(s)
 	values = append(values, pkVal)
 	var err error
 	var res sql.Result
 	if sqlxx.isTx {
-		res, err = sqlxx.tx.Exec(sqls, values...)
+		res, err = sqlxx.tx.ExecContext(sqlxx.context(), sqls, values...)
 	} else {
-		res, err = sqlxx.db.Exec(sqls, values...)
+		res, err = sqlxx.db.ExecContext(sqlxx.context(), sqls, values...)
 	}
 	if err != nil {
 		return nil, err
@@ -474,16 +701,14 @@ func (sqlxx *Sqlxx) UpdatexNotNull(value interface{}) (sql.Result, error) {
 	return res, nil
 }
 
-func (sqlxx *Sqlxx) Updatexw(value interface{}, where interface{}) (sql.Result, error) {
-	s := structs.New(value)
-	w := structs.New(where)
-	sqls, values := buildUpdatew(s, w, false, true)
+func (sqlxx *Sqlxx) Updatexw(value interface{}, where interface{}, opts ...ColumnOption) (sql.Result, error) {
+	sqls, values := buildUpdatew(sqlxx.context(), value, where, false, true, opts...)
 	var err error
 	var res sql.Result
 	if sqlxx.isTx {
-		res, err = sqlxx.tx.Exec(sqls, values...)
+		res, err = sqlxx.tx.ExecContext(sqlxx.context(), sqls, values...)
 	} else {
-		res, err = sqlxx.db.Exec(sqls, values...)
+		res, err = sqlxx.db.ExecContext(sqlxx.context(), sqls, values...)
 	}
 	if err != nil {
 		return nil, err
@@ -491,16 +716,14 @@ func (sqlxx *Sqlxx) Updatexw(value interface{}, where interface{}) (sql.Result,
 	return res, nil
 }
 
-func (sqlxx *Sqlxx) UpdatexwNotNull(value interface{}, where interface{}) (sql.Result, error) {
-	s := structs.New(value)
-	w := structs.New(where)
-	sqls, values := buildUpdatew(s, w, true, true)
+func (sqlxx *Sqlxx) UpdatexwNotNull(value interface{}, where interface{}, opts ...ColumnOption) (sql.Result, error) {
+	sqls, values := buildUpdatew(sqlxx.context(), value, where, true, true, opts...)
 	var err error
 	var res sql.Result
 	if sqlxx.isTx {
-		res, err = sqlxx.tx.Exec(sqls, values...)
+		res, err = sqlxx.tx.ExecContext(sqlxx.context(), sqls, values...)
 	} else {
-		res, err = sqlxx.db.Exec(sqls, values...)
+		res, err = sqlxx.db.ExecContext(sqlxx.context(), sqls, values...)
 	}
 	if err != nil {
 		return nil, err
@@ -512,12 +735,15 @@ func (sqlxx *Sqlxx) Save(args ...interface{}) (sql.Result, error) {
 	if _, ok := sqlxx.dest.(Saver); !ok {
 		return nil, errors.New("must be implement Saver interface")
 	}
-	var err error
+	sqls, args, err := sqlxx.render("save", sqlxx.cache["save"], args)
+	if err != nil {
+		return nil, err
+	}
 	var res sql.Result
 	if sqlxx.isTx {
-		res, err = sqlxx.tx.Exec(sqlxx.cache["save"], args...)
+		res, err = sqlxx.tx.ExecContext(sqlxx.context(), sqls, args...)
 	} else {
-		res, err = sqlxx.db.Exec(sqlxx.cache["save"], args...)
+		res, err = sqlxx.db.ExecContext(sqlxx.context(), sqls, args...)
 	}
 	if err != nil {
 		return nil, err
@@ -525,15 +751,14 @@ func (sqlxx *Sqlxx) Save(args ...interface{}) (sql.Result, error) {
 	return res, nil
 }
 
-func (sqlxx *Sqlxx) Savex(value interface{}) (sql.Result, error) {
-	s := structs.New(value)
-	sqls, args := buildInsert(s, false, false)
+func (sqlxx *Sqlxx) Savex(value interface{}, opts ...ColumnOption) (sql.Result, error) {
+	sqls, args := buildInsert(sqlxx.context(), value, false, false, opts...)
 	var err error
 	var res sql.Result
 	if sqlxx.isTx {
-		res, err = sqlxx.tx.Exec(sqls, args...)
+		res, err = sqlxx.tx.ExecContext(sqlxx.context(), sqls, args...)
 	} else {
-		res, err = sqlxx.db.Exec(sqls, args...)
+		res, err = sqlxx.db.ExecContext(sqlxx.context(), sqls, args...)
 	}
 	if err != nil {
 		return nil, err
@@ -541,15 +766,14 @@ func (sqlxx *Sqlxx) Savex(value interface{}) (sql.Result, error) {
 	return res, nil
 }
 
-func (sqlxx *Sqlxx) SavexNotNull(value interface{}) (sql.Result, error) {
-	s := structs.New(value)
-	sqls, args := buildInsert(s, true, false)
+func (sqlxx *Sqlxx) SavexNotNull(value interface{}, opts ...ColumnOption) (sql.Result, error) {
+	sqls, args := buildInsert(sqlxx.context(), value, true, false, opts...)
 	var err error
 	var res sql.Result
 	if sqlxx.isTx {
-		res, err = sqlxx.tx.Exec(sqls, args...)
+		res, err = sqlxx.tx.ExecContext(sqlxx.context(), sqls, args...)
 	} else {
-		res, err = sqlxx.db.Exec(sqls, args...)
+		res, err = sqlxx.db.ExecContext(sqlxx.context(), sqls, args...)
 	}
 	if err != nil {
 		return nil, err
@@ -561,12 +785,15 @@ func (sqlxx *Sqlxx) Delete(args ...interface{}) (sql.Result, error) {
 	if _, ok := sqlxx.dest.(Deleter); !ok {
 		return nil, errors.New("must be implement Deleter interface")
 	}
-	var err error
+	sqls, args, err := sqlxx.render("delete", sqlxx.cache["delete"], args)
+	if err != nil {
+		return nil, err
+	}
 	var res sql.Result
 	if sqlxx.isTx {
-		res, err = sqlxx.tx.Exec(sqlxx.cache["delete"], args...)
+		res, err = sqlxx.tx.ExecContext(sqlxx.context(), sqls, args...)
 	} else {
-		res, err = sqlxx.db.Exec(sqlxx.cache["delete"], args...)
+		res, err = sqlxx.db.ExecContext(sqlxx.context(), sqls, args...)
 	}
 	if err != nil {
 		return nil, err
@@ -575,14 +802,13 @@ func (sqlxx *Sqlxx) Delete(args ...interface{}) (sql.Result, error) {
 }
 
 func (sqlxx *Sqlxx) Deletex(value interface{}) (sql.Result, error) {
-	s := structs.New(value)
-	sqls, values := buildDelete(s)
+	sqls, values := buildDelete(sqlxx.context(), value)
 	var err error
 	var res sql.Result
 	if sqlxx.isTx {
-		res, err = sqlxx.tx.Exec(sqls, values...)
+		res, err = sqlxx.tx.ExecContext(sqlxx.context(), sqls, values...)
 	} else {
-		res, err = sqlxx.db.Exec(sqls, values...)
+		res, err = sqlxx.db.ExecContext(sqlxx.context(), sqls, values...)
 	}
 	if err != nil {
 		return nil, err
@@ -591,5 +817,10 @@ func (sqlxx *Sqlxx) Deletex(value interface{}) (sql.Result, error) {
 }
 
 func (sqlxx *Sqlxx) Query() *query {
-	return newQuery(sqlxx.dest, sqlxx.db, sqlxx.fieldNames)
+	q := newQuery(sqlxx.dest, sqlxx.db, sqlxx.fieldNames)
+	q.ctx = sqlxx.ctx
+	if sqlxx.isTx {
+		q.tx = sqlxx.tx
+	}
+	return q
 }
@@ -1,12 +1,14 @@
 package sqlxx
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	"log"
 	"testing"
+	"time"
 )
 
 /**
@@ -146,3 +148,136 @@ func TestSqlxx_Query(t *testing.T) {
 	}
 	log.Println(ui)
 }
+
+func TestBuildUpdate_Only(t *testing.T) {
+	s, values := buildUpdate(context.Background(), &UserInfo{Id: 2, Name: "测试"}, false, false, Only("name", "age"))
+	if want := "UPDATE user SET name = ? ,age = ? WHERE id = ?"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	if len(values) != 2 || values[1] != 0 {
+		t.Errorf("unexpected values %v", values)
+	}
+}
+
+func TestBuildInsert_Omit(t *testing.T) {
+	s, _ := buildInsert(context.Background(), &UserInfo{Name: "abc"}, false, false, Omit("email", "address"))
+	if want := "INSERT INTO user(name,age) VALUES (?,?)"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
+
+func TestBuildSelect_Select(t *testing.T) {
+	s, values := buildSelect(context.Background(), &UserInfo{Id: 2, Age: 10}, false, true, Only("id", "name"))
+	if want := "SELECT id,name FROM user WHERE id = ? AND age = ?"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	if len(values) != 2 {
+		t.Errorf("unexpected values %v", values)
+	}
+}
+
+type Article struct {
+	Id        int    `db:"id"`
+	Title     string `db:"title"`
+	Slug      string `db:"slug" sqlxx:"readonly"`
+	CreatedBy string `db:"created_by" sqlxx:"insertonly"`
+	State     int    `db:"state" sqlxx:"default:1"`
+	Summary   string `db:"summary" sqlxx:"omitempty"`
+	Cache     string `sqlxx:"-"`
+}
+
+func TestBuildInsert_TagOptions(t *testing.T) {
+	s, _ := buildInsert(context.Background(), &Article{Title: "abc", CreatedBy: "admin"}, false, false)
+	if want := "INSERT INTO article(title,created_by) VALUES (?,?)"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	s, _ = buildInsert(context.Background(), &Article{Title: "abc", State: 2, Summary: "x"}, false, false)
+	if want := "INSERT INTO article(title,created_by,state,summary) VALUES (?,?,?,?)"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
+
+func TestBuildUpdate_TagOptions(t *testing.T) {
+	s, _ := buildUpdate(context.Background(), &Article{Id: 1, Title: "abc", CreatedBy: "admin"}, false, false)
+	if want := "UPDATE article SET title = ? ,state = ? WHERE id = ?"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
+
+type Invoice struct {
+	Id     int `db:"id"`
+	Amount int `db:"amount"`
+}
+
+type monthKey struct{}
+
+func (i *Invoice) TableNameContext(ctx context.Context) string {
+	if month, ok := ctx.Value(monthKey{}).(string); ok {
+		return "orders_" + month
+	}
+	return "orders"
+}
+
+type Account struct {
+	Id   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+func (a *Account) TableName() string {
+	return "t_account"
+}
+
+func TestSetTableName(t *testing.T) {
+	ctx := context.WithValue(context.Background(), monthKey{}, "202610")
+	s, _ := buildInsert(ctx, &Invoice{Amount: 1}, false, false)
+	if want := "INSERT INTO orders_202610(amount) VALUES (?)"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	if got := New(&Invoice{}, db()).WithContext(ctx).table(); got != "orders_202610" {
+		t.Errorf("got %q", got)
+	}
+	s, _ = buildInsert(context.Background(), &Account{Name: "abc"}, false, false)
+	if want := "INSERT INTO t_account(name) VALUES (?)"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
+
+type money struct {
+	cents int64
+}
+
+func (m *money) IsZero() bool {
+	return m.cents == 0
+}
+
+func TestIsZero(t *testing.T) {
+	name := ""
+	cases := []struct {
+		value interface{}
+		zero  bool
+	}{
+		{uint(0), true},
+		{uint8(1), false},
+		{int8(0), true},
+		{false, true},
+		{true, false},
+		{time.Time{}, true},
+		{time.Now(), false},
+		{(*string)(nil), true},
+		{&name, false},
+		{[]byte{}, true},
+		{[]byte("a"), false},
+		{sql.NullString{}, true},
+		{sql.NullString{String: "", Valid: true}, false},
+		{sql.NullTime{}, true},
+		{money{}, true},
+		{money{cents: 1}, false},
+		{(*time.Time)(nil), true},
+		{(*money)(nil), true},
+	}
+	for i, c := range cases {
+		if got := isZero(c.value); got != c.zero {
+			t.Errorf("case %d: isZero(%#v) = %v, want %v", i, c.value, got, c.zero)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package sqlxx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode"
+)
+
+// Mapper SQL may be a text/template rendered with the single parameter
+// struct or map passed to the call, besides the usual actions it has:
+//
+//	{{bind .Name}}          a placeholder bound to .Name
+//	{{in .Ids}}             (?, ?, ?) bound to the elements of .Ids, an
+//	                        error if .Ids is empty
+//	{{ident .Sort}}         .Sort checked to be a plain column name
+//	{{where}}...{{endwhere}} WHERE and the conditions without the leading
+//	                        AND or OR, nothing if there are none
+//	{{set}}...{{endset}}    SET and the assignments without the trailing
+//	                        comma
+//
+// There are no foreach or choose helpers, on purpose: range and
+// if / else if / else cover them and in covers the IN lists.
+
+const (
+	whereStart = "\x00where\x00"
+	whereEnd   = "\x00/where\x00"
+	setStart   = "\x00set\x00"
+	setEnd     = "\x00/set\x00"
+)
+
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+func isTemplate(sql string) bool {
+	return strings.Contains(sql, "{{")
+}
+
+// templateFuncs returns the functions of a render, bind and in append
+// to args as the template executes so args follow the placeholders.
+func templateFuncs(args *[]interface{}) template.FuncMap {
+	return template.FuncMap{
+		"bind": func(v interface{}) string {
+			*args = append(*args, v)
+			return "?"
+		},
+		"in": func(v interface{}) (string, error) {
+			rv := reflect.ValueOf(v)
+			if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				return "", fmt.Errorf("in needs a slice, got %T", v)
+			}
+			// (NULL) would make NOT IN match nothing, guard it with if
+			if rv.Len() == 0 {
+				return "", errors.New("in needs a non empty slice")
+			}
+			for i := 0; i < rv.Len(); i++ {
+				*args = append(*args, rv.Index(i).Interface())
+			}
+			return "(?" + strings.Repeat(", ?", rv.Len()-1) + ")", nil
+		},
+		"ident": func(s string) (string, error) {
+			if !identPattern.MatchString(s) {
+				return "", fmt.Errorf("invalid identifier %q", s)
+			}
+			return s, nil
+		},
+		"where":    func() string { return whereStart },
+		"endwhere": func() string { return whereEnd },
+		"set":      func() string { return setStart },
+		"endset":   func() string { return setEnd },
+	}
+}
+
+type templateKey struct {
+	model reflect.Type
+	name  string
+}
+
+type parsedTemplate struct {
+	text string
+	tmpl *template.Template
+}
+
+// templates caches the parsed templates per model type and query name.
+var templates sync.Map
+
+func parseTemplate(model interface{}, name string, text string) (*template.Template, error) {
+	key := templateKey{reflect.TypeOf(model), name}
+	if v, ok := templates.Load(key); ok && v.(parsedTemplate).text == text {
+		return v.(parsedTemplate).tmpl, nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(templateFuncs(nil)).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	templates.Store(key, parsedTemplate{text, tmpl})
+	return tmpl, nil
+}
+
+// renderTemplate executes the template name of model with param and
+// returns the SQL and its args in placeholder order.
+func renderTemplate(model interface{}, name string, text string, param interface{}) (string, []interface{}, error) {
+	tmpl, err := parseTemplate(model, name, text)
+	if err != nil {
+		return "", nil, err
+	}
+	tmpl, err = tmpl.Clone()
+	if err != nil {
+		return "", nil, err
+	}
+	var args []interface{}
+	var buf bytes.Buffer
+	if err := tmpl.Funcs(templateFuncs(&args)).Execute(&buf, param); err != nil {
+		return "", nil, err
+	}
+	sql, err := trimBlocks(buf.String())
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
+}
+
+// trimBlocks replaces the where and set blocks, innermost first.
+func trimBlocks(sql string) (string, error) {
+	for {
+		start, open, end, keyword := strings.LastIndex(sql, whereStart), whereStart, whereEnd, "WHERE "
+		if i := strings.LastIndex(sql, setStart); i > start {
+			start, open, end, keyword = i, setStart, setEnd, "SET "
+		}
+		if start < 0 {
+			if strings.Contains(sql, "\x00") {
+				return "", errors.New("unbalanced where or set block")
+			}
+			return strings.TrimSpace(sql), nil
+		}
+		j := strings.Index(sql[start:], end)
+		if j < 0 {
+			return "", errors.New("unbalanced where or set block")
+		}
+		body := strings.TrimSpace(sql[start+len(open) : start+j])
+		if keyword == "WHERE " {
+			body = trimWord(trimWord(body, "AND"), "OR")
+		} else {
+			body = strings.TrimSpace(strings.TrimSuffix(body, ","))
+		}
+		before, after := sql[:start], sql[start+j+len(end):]
+		if body != "" {
+			body = keyword + body
+			if before != "" && !unicode.IsSpace(rune(before[len(before)-1])) {
+				body = " " + body
+			}
+			if after != "" && !unicode.IsSpace(rune(after[0])) {
+				body += " "
+			}
+		}
+		sql = before + body + after
+	}
+}
+
+// trimWord removes the leading word of s, in any case, if it is followed
+// by a space.
+func trimWord(s, word string) string {
+	if len(s) > len(word) && strings.EqualFold(s[:len(word)], word) && unicode.IsSpace(rune(s[len(word)])) {
+		return strings.TrimSpace(s[len(word):])
+	}
+	return s
+}
+
+// render returns the SQL cached under key, rendered with the parameter
+// in args if it is a template or bound from it if it has :name
+// parameters.
+func (sqlxx *Sqlxx) render(key string, sql string, args []interface{}) (string, []interface{}, error) {
+	if !isTemplate(sql) {
+		if isNamed(sql, args) {
+			return sqlxx.bindNamed(sql, args[0])
+		}
+		return sql, args, nil
+	}
+	if len(args) != 1 {
+		return "", nil, errors.New("template query takes one parameter struct or map")
+	}
+	sql, args, err := renderTemplate(sqlxx.dest, key, sql, args[0])
+	if err != nil {
+		return "", nil, err
+	}
+	return sqlxx.db.Rebind(sql), args, nil
+}
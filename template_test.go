@@ -0,0 +1,77 @@
+package sqlxx
+
+import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"reflect"
+	"testing"
+)
+
+type UserFilter struct {
+	Name string
+	Ages []int
+	Sort string
+}
+
+const userSearch = `SELECT * FROM user
+{{- where}}
+	{{- if .Name}} AND name = {{bind .Name}}{{end}}
+	{{- if .Ages}} AND age IN {{in .Ages}}{{end}}
+{{- endwhere}}
+{{- if .Sort}} ORDER BY {{ident .Sort}}{{else}} ORDER BY id{{end}}`
+
+func TestRenderTemplate(t *testing.T) {
+	sqls, args, err := renderTemplate(&UserInfo{}, "select", userSearch, UserFilter{Name: "abc", Ages: []int{10, 11}, Sort: "age"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT * FROM user WHERE name = ? AND age IN (?, ?) ORDER BY age"; sqls != want {
+		t.Errorf("got %s, want %s", sqls, want)
+	}
+	if want := []interface{}{"abc", 10, 11}; !reflect.DeepEqual(args, want) {
+		t.Errorf("got %v, want %v", args, want)
+	}
+
+	sqls, args, err = renderTemplate(&UserInfo{}, "select", userSearch, UserFilter{Ages: []int{10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT * FROM user WHERE age IN (?) ORDER BY id"; sqls != want || len(args) != 1 {
+		t.Errorf("got %s %v, want %s", sqls, args, want)
+	}
+
+	sqls, _, err = renderTemplate(&UserInfo{}, "select", userSearch, UserFilter{})
+	if want := "SELECT * FROM user ORDER BY id"; err != nil || sqls != want {
+		t.Errorf("got %s %v, want %s", sqls, err, want)
+	}
+
+	if _, _, err := renderTemplate(&UserInfo{}, "select", userSearch, UserFilter{Sort: "age; DROP TABLE user"}); err == nil {
+		t.Error("want error for an invalid identifier")
+	}
+
+	if _, _, err := renderTemplate(&UserInfo{}, "in", "SELECT * FROM user WHERE age NOT IN {{in .Ages}}", UserFilter{}); err == nil {
+		t.Error("want error for an empty in")
+	}
+}
+
+func TestSqlxx_RenderSet(t *testing.T) {
+	sqlxx := &Sqlxx{dest: &UserInfo{}, db: sqlx.NewDb(&sql.DB{}, "postgres")}
+	update := `UPDATE user {{set}}
+	{{- if .name}} name = {{bind .name}},{{end}}
+	{{- if .age}} age = {{bind .age}},{{end}}
+{{- endset}} WHERE id = {{bind .id}}`
+	sqls, args, err := sqlxx.render("update", update, []interface{}{map[string]interface{}{"name": "", "age": 12, "id": 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "UPDATE user SET age = $1 WHERE id = $2"; sqls != want {
+		t.Errorf("got %s, want %s", sqls, want)
+	}
+	if want := []interface{}{12, 2}; !reflect.DeepEqual(args, want) {
+		t.Errorf("got %v, want %v", args, want)
+	}
+
+	if _, _, err := sqlxx.render("update", update, []interface{}{1, 2}); err == nil {
+		t.Error("want error for a template given two args")
+	}
+}
@@ -0,0 +1,195 @@
+package sqlxx
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"strconv"
+)
+
+type updateQuery struct {
+	sqlxx *Sqlxx
+	q     *query
+	sets  []sqlValue
+	limit int
+	all   bool
+}
+
+type deleteQuery struct {
+	sqlxx *Sqlxx
+	q     *query
+	limit int
+	all   bool
+}
+
+func (sqlxx *Sqlxx) UpdateWhere() *updateQuery {
+	return &updateQuery{sqlxx: sqlxx, q: newQuery2(nil, sqlxx.db)}
+}
+
+func (sqlxx *Sqlxx) DeleteWhere() *deleteQuery {
+	return &deleteQuery{sqlxx: sqlxx, q: newQuery2(nil, sqlxx.db)}
+}
+
+func (sqlxx *Sqlxx) exec(sqls string, args []interface{}) (sql.Result, error) {
+	if sqlxx.isTx {
+		return sqlxx.tx.ExecContext(sqlxx.context(), sqlxx.tx.Rebind(sqls), args...)
+	}
+	return sqlxx.db.ExecContext(sqlxx.context(), sqlxx.db.Rebind(sqls), args...)
+}
+
+// Set assigns value to col; an Expr value such as Expr("count + ?", 1)
+// is written as is.
+func (u *updateQuery) Set(col string, value interface{}) *updateQuery {
+	u.sets = append(u.sets, newSqlValue(col, Equal, value, Where))
+	return u
+}
+
+func (u *updateQuery) Where(field interface{}, cond condition, value interface{}) *updateQuery {
+	u.q.Where(field, cond, value)
+	return u
+}
+
+func (u *updateQuery) Between(field interface{}, value interface{}, value2 interface{}) *updateQuery {
+	u.q.Between(field, value, value2)
+	return u
+}
+
+func (u *updateQuery) WhereOr(groups ...*query) *updateQuery {
+	u.q.WhereOr(groups...)
+	return u
+}
+
+func (u *updateQuery) WhereExists(sub *query) *updateQuery {
+	u.q.WhereExists(sub)
+	return u
+}
+
+func (u *updateQuery) WhereNotExists(sub *query) *updateQuery {
+	u.q.WhereNotExists(sub)
+	return u
+}
+
+func (u *updateQuery) Limit(n int) *updateQuery {
+	u.limit = n
+	return u
+}
+
+// AllowAll lets the update run without any condition.
+func (u *updateQuery) AllowAll() *updateQuery {
+	u.all = true
+	return u
+}
+
+func (u *updateQuery) build() (string, error) {
+	if len(u.sets) == 0 {
+		return "", errors.New("update must be set column")
+	}
+	if !hasCondition(u.q.where) && !u.all {
+		return "", errors.New("refuse to update without condition, use AllowAll")
+	}
+	if u.limit > 0 && u.q.dialect() != MySQL {
+		return "", errors.New("update limit only supported by mysql")
+	}
+
+	u.q.whereValue = nil
+	var sb bytes.Buffer
+	sb.WriteString("UPDATE ")
+	sb.WriteString(u.sqlxx.table())
+	sb.WriteString(" SET ")
+	for i, s := range u.sets {
+		if err := u.q.writeCondition(&sb, s); err != nil {
+			return "", err
+		}
+		if i != len(u.sets)-1 {
+			sb.WriteString(",")
+		}
+	}
+	if len(u.q.where) > 0 {
+		sb.WriteString(" WHERE ")
+		if err := u.q.writeConditions(&sb, u.q.where); err != nil {
+			return "", err
+		}
+	}
+	if u.limit > 0 {
+		sb.WriteString(" LIMIT ")
+		sb.WriteString(strconv.Itoa(u.limit))
+	}
+	return sb.String(), nil
+}
+
+func (u *updateQuery) Exec() (sql.Result, error) {
+	sqls, err := u.build()
+	if err != nil {
+		return nil, err
+	}
+	return u.sqlxx.exec(sqls, u.q.whereValue)
+}
+
+func (d *deleteQuery) Where(field interface{}, cond condition, value interface{}) *deleteQuery {
+	d.q.Where(field, cond, value)
+	return d
+}
+
+func (d *deleteQuery) Between(field interface{}, value interface{}, value2 interface{}) *deleteQuery {
+	d.q.Between(field, value, value2)
+	return d
+}
+
+func (d *deleteQuery) WhereOr(groups ...*query) *deleteQuery {
+	d.q.WhereOr(groups...)
+	return d
+}
+
+func (d *deleteQuery) WhereExists(sub *query) *deleteQuery {
+	d.q.WhereExists(sub)
+	return d
+}
+
+func (d *deleteQuery) WhereNotExists(sub *query) *deleteQuery {
+	d.q.WhereNotExists(sub)
+	return d
+}
+
+func (d *deleteQuery) Limit(n int) *deleteQuery {
+	d.limit = n
+	return d
+}
+
+// AllowAll lets the delete run without any condition.
+func (d *deleteQuery) AllowAll() *deleteQuery {
+	d.all = true
+	return d
+}
+
+func (d *deleteQuery) build() (string, error) {
+	if !hasCondition(d.q.where) && !d.all {
+		return "", errors.New("refuse to delete without condition, use AllowAll")
+	}
+	if d.limit > 0 && d.q.dialect() != MySQL {
+		return "", errors.New("delete limit only supported by mysql")
+	}
+
+	d.q.whereValue = nil
+	var sb bytes.Buffer
+	sb.WriteString("DELETE FROM ")
+	sb.WriteString(d.sqlxx.table())
+	if len(d.q.where) > 0 {
+		sb.WriteString(" WHERE ")
+		if err := d.q.writeConditions(&sb, d.q.where); err != nil {
+			return "", err
+		}
+	}
+	if d.limit > 0 {
+		sb.WriteString(" LIMIT ")
+		sb.WriteString(strconv.Itoa(d.limit))
+	}
+	return sb.String(), nil
+}
+
+func (d *deleteQuery) Exec() (sql.Result, error) {
+	sqls, err := d.build()
+	if err != nil {
+		return nil, err
+	}
+	return d.sqlxx.exec(sqls, d.q.whereValue)
+}
@@ -0,0 +1,71 @@
+package sqlxx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSqlxx_UpdateWhere(t *testing.T) {
+	u := userDao.UpdateWhere().Set("name", "测试").Set("age", Expr("age + ?", 1)).
+		Where("age", GreaterThan, 10).Between("id", 1, 100).
+		WhereOr(Cond().Where("name", Like, "abc%"), Cond().Where("email", IsNull, nil)).
+		Limit(10)
+	s, err := u.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "UPDATE user SET name = ? ,age = age + ?  WHERE age > ?  AND id between ? AND ?  AND ((name like ? ) OR (email is null ))  LIMIT 10"
+	if s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	if len(u.q.whereValue) != 6 {
+		t.Errorf("unexpected args %v", u.q.whereValue)
+	}
+
+	if _, err := userDao.UpdateWhere().Set("age", 1).build(); err == nil {
+		t.Error("expected error for unconditioned update")
+	}
+	s, err = userDao.UpdateWhere().Set("age", 1).AllowAll().build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "UPDATE user SET age = ? "; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
+
+func TestSqlxx_DeleteWhere(t *testing.T) {
+	s, err := userDao.DeleteWhere().Where("age", LessThan, 10).Where("name", In, Cond().Select("name").From("black_list")).build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "DELETE FROM user WHERE age < ?  AND name in (SELECT name FROM black_list) "; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	if _, err := userDao.DeleteWhere().build(); err == nil {
+		t.Error("expected error for unconditioned delete")
+	}
+	if _, err := userDao.DeleteWhere().WhereOr().build(); err == nil || err.Error() != "refuse to delete without condition, use AllowAll" {
+		t.Errorf("got %v for delete with empty WhereOr", err)
+	}
+	if _, err := userDao.DeleteWhere().Where("age", LessThan, 10).WhereOr(Cond()).build(); err == nil {
+		t.Error("expected error for WhereOr with an empty Cond")
+	}
+}
+
+func TestSqlxx_UpdateWhereIn(t *testing.T) {
+	u := userDao.UpdateWhere().Set("age", 1).Where("id", In, []int{1, 2, 3}).Where("name", NotIn, []string{"a"})
+	s, err := u.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "UPDATE user SET age = ?  WHERE id in (?, ?, ?)  AND name not in (?) "; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	if want := []interface{}{1, 1, 2, 3, "a"}; !reflect.DeepEqual(u.q.whereValue, want) {
+		t.Errorf("got args %v, want %v", u.q.whereValue, want)
+	}
+	if _, err := userDao.UpdateWhere().Set("age", 1).Where("id", In, []int{}).build(); err == nil {
+		t.Error("expected error for in with an empty slice")
+	}
+}
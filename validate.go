@@ -0,0 +1,182 @@
+package sqlxx
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type Option func(*Sqlxx)
+
+// ValidateMappers makes New and NewE check the Mapper SQL of the model
+// against the database, New panics with every problem found and NewE
+// returns them.
+func ValidateMappers() Option {
+	return func(sqlxx *Sqlxx) {
+		sqlxx.validate = true
+	}
+}
+
+// Validate prepares each Mapper statement and checks that the columns
+// selected by SelectOne and Select map to model fields. Templates are only
+// parsed, never prepared, as their SQL depends on the parameters.
+func (sqlxx *Sqlxx) Validate() error {
+	keys := make([]string, 0, len(sqlxx.cache))
+	for k := range sqlxx.cache {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	fields := sqlxx.db.Mapper.TypeMap(reflect.TypeOf(sqlxx.dest)).Names
+	for _, k := range keys {
+		q := sqlxx.cache[k]
+		if isTemplate(q) {
+			if _, err := parseTemplate(sqlxx.dest, k, q); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", k, err))
+			}
+			continue
+		}
+		if err := sqlxx.prepare(q); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", k, err))
+			continue
+		}
+		if k != "select" && k != "selectOne" {
+			continue
+		}
+		for _, c := range selectColumns(q) {
+			if _, ok := fields[c]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: column %s has no field", k, c))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid mapper sql of %T:\n%s", sqlxx.dest, strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// prepare prepares and closes q, its :name parameters bound to nothing.
+func (sqlxx *Sqlxx) prepare(q string) error {
+	if names := namedParams(q); len(names) > 0 {
+		arg := make(map[string]interface{}, len(names))
+		for _, name := range names {
+			arg[name] = nil
+		}
+		var err error
+		if q, _, err = sqlx.Named(q, arg); err != nil {
+			return err
+		}
+	}
+	stmt, err := sqlxx.db.PreparexContext(sqlxx.context(), sqlxx.db.Rebind(q))
+	if err != nil {
+		return err
+	}
+	return stmt.Close()
+}
+
+// selectColumns returns the names the select list of q scans into, its
+// aliases or its column names without table qualifier. * is skipped.
+func selectColumns(q string) []string {
+	items, err := selectList(q)
+	if err != nil {
+		return nil
+	}
+	var cs []string
+	for _, item := range items {
+		if strings.HasSuffix(item, "*") {
+			continue
+		}
+		name := item
+		if i := lastTopLevelWord(item, "as"); i >= 0 {
+			name = strings.TrimSpace(item[i+2:])
+		} else if j := strings.LastIndex(item, "."); j >= 0 && identPattern.MatchString(strings.Trim(item, "`\"")) {
+			name = item[j+1:]
+		}
+		cs = append(cs, strings.Trim(name, "`\""))
+	}
+	return cs
+}
+
+// selectList splits the select list of q on its top level commas.
+func selectList(q string) ([]string, error) {
+	start := topLevelWord(q, "select", 0)
+	if start < 0 {
+		return nil, errors.New("not a select")
+	}
+	start += len("select")
+	end := topLevelWord(q, "from", start)
+	if end < 0 {
+		end = len(q)
+	}
+	list := strings.TrimSpace(q[start:end])
+	if w := "distinct"; len(list) > len(w) && strings.EqualFold(list[:len(w)], w) && unicode.IsSpace(rune(list[len(w)])) {
+		list = list[len(w):]
+	}
+
+	var items []string
+	depth, from := 0, 0
+	for i := 0; i < len(list); i++ {
+		switch c := list[i]; c {
+		case '\'', '"', '`':
+			if j := strings.IndexByte(list[i+1:], c); j >= 0 {
+				i += j + 1
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(list[from:i]))
+				from = i + 1
+			}
+		}
+	}
+	return append(items, strings.TrimSpace(list[from:])), nil
+}
+
+// topLevelWord returns the index of the first word of q, in any case,
+// outside parentheses and quotes from index start on, -1 if none.
+func topLevelWord(q string, word string, start int) int {
+	depth := 0
+	for i := start; i < len(q); i++ {
+		switch c := q[i]; c {
+		case '\'', '"', '`':
+			if j := strings.IndexByte(q[i+1:], c); j >= 0 {
+				i += j + 1
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+		default:
+			if depth == 0 && isWordAt(q, word, i) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func lastTopLevelWord(q string, word string) int {
+	last := -1
+	for i := topLevelWord(q, word, 0); i >= 0; i = topLevelWord(q, word, i+len(word)) {
+		last = i
+	}
+	return last
+}
+
+func isWordAt(q string, word string, i int) bool {
+	if i+len(word) > len(q) || !strings.EqualFold(q[i:i+len(word)], word) {
+		return false
+	}
+	if i > 0 && isBindRune(rune(q[i-1])) {
+		return false
+	}
+	return i+len(word) == len(q) || !isBindRune(rune(q[i+len(word)]))
+}
@@ -0,0 +1,114 @@
+package sqlxx
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Note struct {
+	Id    int64  `db:"id"`
+	Title string `db:"title"`
+}
+
+func (n *Note) SelectOne() string {
+	return "SELECT n.id, titel AS title FROM note n WHERE id = :id"
+}
+
+func (n *Note) Select() string {
+	return "SELECT id, upper(title), length(title) AS size FROM note"
+}
+
+func (n *Note) Count() string {
+	return "SELECT count(*) FROM notes"
+}
+
+func (n *Note) Delete() string {
+	return "DELETE FROM note WHERE {{if .Id}}id = {{bind .Id}}"
+}
+
+// prepareDriver fails to prepare the statements naming one of its unknown
+// identifiers, standing in for a database schema.
+type prepareDriver struct {
+	unknown []string
+}
+
+func (d prepareDriver) Open(string) (driver.Conn, error) { return d, nil }
+
+func (d prepareDriver) Prepare(query string) (driver.Stmt, error) {
+	for _, name := range d.unknown {
+		for _, f := range strings.FieldsFunc(query, func(r rune) bool { return r == ' ' || r == ',' }) {
+			if f == name {
+				return nil, fmt.Errorf("no such name: %s", name)
+			}
+		}
+	}
+	return prepareStmt{}, nil
+}
+
+func (prepareDriver) Close() error              { return nil }
+func (prepareDriver) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type prepareStmt struct{}
+
+func (prepareStmt) Close() error  { return nil }
+func (prepareStmt) NumInput() int { return -1 }
+func (prepareStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (prepareStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func init() {
+	sql.Register("sqlxx_prepare", prepareDriver{unknown: []string{"notes", "titel"}})
+}
+
+func TestSqlxx_Validate(t *testing.T) {
+	db, err := sqlx.Open("sqlxx_prepare", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = New(&Note{}, db).Validate()
+	if err == nil {
+		t.Fatal("want validation errors")
+	}
+	for _, want := range []string{
+		"count: no such name: notes",
+		"delete: template: delete:1: unexpected EOF",
+		"select: column upper(title) has no field",
+		"select: column size has no field",
+		"selectOne: no such name: titel",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%q not reported in\n%v", want, err)
+		}
+	}
+
+	if _, err := NewE(&Note{}, db, ValidateMappers()); err == nil {
+		t.Error("NewE with ValidateMappers returned no error")
+	}
+	if _, err := NewE(&Note{}, db); err != nil {
+		t.Errorf("NewE without ValidateMappers returned %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("New with ValidateMappers did not panic")
+		}
+	}()
+	New(&Note{}, db, ValidateMappers())
+}
+
+func TestSelectColumns(t *testing.T) {
+	got := selectColumns("select distinct u.id, `name`, count(*) AS c, coalesce(a, ',') as \"addr.city\", u.* from user u where x in (select y from z)")
+	if want := []string{"id", "name", "c", "addr.city"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,142 @@
+package sqlxx
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+const (
+	UnboundedPreceding = "UNBOUNDED PRECEDING"
+	UnboundedFollowing = "UNBOUNDED FOLLOWING"
+	CurrentRow         = "CURRENT ROW"
+)
+
+func Preceding(n int) string {
+	return strconv.Itoa(n) + " PRECEDING"
+}
+
+func Following(n int) string {
+	return strconv.Itoa(n) + " FOLLOWING"
+}
+
+type windowSpec struct {
+	partition []string
+	order     []string
+	frame     string
+}
+
+func Window() *windowSpec {
+	return &windowSpec{}
+}
+
+func (w *windowSpec) PartitionBy(field ...string) *windowSpec {
+	w.partition = append(w.partition, field...)
+	return w
+}
+
+func (w *windowSpec) OrderBy(field string, desc string) *windowSpec {
+	w.order = append(w.order, field+" "+desc)
+	return w
+}
+
+func (w *windowSpec) Rows(start, end string) *windowSpec {
+	w.frame = "ROWS BETWEEN " + start + " AND " + end
+	return w
+}
+
+func (w *windowSpec) Range(start, end string) *windowSpec {
+	w.frame = "RANGE BETWEEN " + start + " AND " + end
+	return w
+}
+
+func (w *windowSpec) String() string {
+	var parts []string
+	if len(w.partition) > 0 {
+		parts = append(parts, "PARTITION BY "+strings.Join(w.partition, ","))
+	}
+	if len(w.order) > 0 {
+		parts = append(parts, "ORDER BY "+strings.Join(w.order, ","))
+	}
+	if w.frame != "" {
+		parts = append(parts, w.frame)
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+type windowFunc struct {
+	fn     string
+	over   *windowSpec
+	window string
+	alias  string
+}
+
+func RowNumber() *windowFunc {
+	return &windowFunc{fn: "ROW_NUMBER()"}
+}
+
+func Rank() *windowFunc {
+	return &windowFunc{fn: "RANK()"}
+}
+
+func DenseRank() *windowFunc {
+	return &windowFunc{fn: "DENSE_RANK()"}
+}
+
+func Lag(col string, offset int) *windowFunc {
+	return &windowFunc{fn: "LAG(" + col + ", " + strconv.Itoa(offset) + ")"}
+}
+
+func Lead(col string, offset int) *windowFunc {
+	return &windowFunc{fn: "LEAD(" + col + ", " + strconv.Itoa(offset) + ")"}
+}
+
+func Sum(col string) *windowFunc {
+	return &windowFunc{fn: "SUM(" + col + ")"}
+}
+
+func Avg(col string) *windowFunc {
+	return &windowFunc{fn: "AVG(" + col + ")"}
+}
+
+func Min(col string) *windowFunc {
+	return &windowFunc{fn: "MIN(" + col + ")"}
+}
+
+func Max(col string) *windowFunc {
+	return &windowFunc{fn: "MAX(" + col + ")"}
+}
+
+func (f *windowFunc) Over(w *windowSpec) *windowFunc {
+	f.over, f.window = w, ""
+	return f
+}
+
+// OverWindow refers to a window defined on the query with query.Window.
+func (f *windowFunc) OverWindow(name string) *windowFunc {
+	f.over, f.window = nil, name
+	return f
+}
+
+func (f *windowFunc) As(alias string) *windowFunc {
+	f.alias = alias
+	return f
+}
+
+func (f *windowFunc) String() string {
+	var sb bytes.Buffer
+	sb.WriteString(f.fn)
+	sb.WriteString(" OVER ")
+	if f.window != "" {
+		sb.WriteString(f.window)
+	} else if f.over != nil {
+		sb.WriteString(f.over.String())
+	} else {
+		sb.WriteString("()")
+	}
+	if f.alias != "" {
+		sb.WriteString(" AS ")
+		sb.WriteString(f.alias)
+	}
+	return sb.String()
+}